@@ -13,6 +13,6 @@ require (
 	github.com/spf13/cobra v0.0.7
 	github.com/stretchr/testify v1.5.1
 	golang.org/x/sys v0.0.0-20200409092240-59c9f1ba88fa // indirect
-	gopkg.in/yaml.v2 v2.2.8 // indirect
+	gopkg.in/yaml.v2 v2.2.8
 	mvdan.cc/sh v2.6.4+incompatible
 )
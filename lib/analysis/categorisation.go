@@ -2,6 +2,7 @@ package analysis
 
 import (
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -25,79 +26,289 @@ func (c IssueCategories) String() string {
 }
 
 type IssueCategory struct {
-	Linter         string
+	Linter string
+	// Representative is the template shared by the category's issues, or the one of its most frequent
+	// issues when similar templates were merged.
 	Representative string
 	Issues         []*result.Issue
 }
 
+// Key identifies the category independently of the issues that it contains. It consists of the
+// category's linter and representative template, e.g. 'lll: line is <number> characters'.
+func (c *IssueCategory) Key() string {
+	return c.Linter + ": " + c.Representative
+}
+
 func (c *IssueCategory) String() string {
 	return fmt.Sprintf("%s - %s - %d occurrences", c.Linter, c.Representative, len(c.Issues))
 }
 
-func IssueRanking(view *report.View, tolerance int) IssueCategories {
+// DirectoryOccurrences holds the issues of a category that are located in the files of a single
+// directory, sorted by file, line and column.
+type DirectoryOccurrences struct {
+	Directory string
+	Issues    []*result.Issue
+}
+
+// Occurrences groups the issues of the category by the directory in which they are located. The
+// directories are sorted by path.
+func (c *IssueCategory) Occurrences() []*DirectoryOccurrences {
+	byDirectory := map[string]*DirectoryOccurrences{}
+	for _, issue := range c.Issues {
+		directory := filepath.Dir(issue.Pos.Filename)
+
+		occurrences, ok := byDirectory[directory]
+		if !ok {
+			occurrences = &DirectoryOccurrences{Directory: directory}
+			byDirectory[directory] = occurrences
+		}
+
+		occurrences.Issues = append(occurrences.Issues, issue)
+	}
+
+	var directories []*DirectoryOccurrences
+	for _, occurrences := range byDirectory {
+		sort.Slice(occurrences.Issues, func(i int, j int) bool {
+			return issueLess(occurrences.Issues[i], occurrences.Issues[j])
+		})
+
+		directories = append(directories, occurrences)
+	}
+
+	sort.Slice(directories, func(i int, j int) bool { return directories[i].Directory < directories[j].Directory })
+
+	return directories
+}
+
+// CategoryMatrix holds the number of issues of each of a set of categories in each of the SubViews of
+// a View. It shows where the issues of each category are concentrated.
+type CategoryMatrix struct {
+	Categories IssueCategories
+	// Paths lists the paths of the SubViews that contain issues of any of the categories, sorted.
+	Paths []string
+	// Counts holds the number of issues of each category, in the same order as Categories, for each of
+	// the Paths.
+	Counts map[string][]int
+}
+
+// NewCategoryMatrix returns the distribution over the SubViews of the given View of the issues of the
+// 'top' first categories, or of all of them if 'top' is not positive.
+func NewCategoryMatrix(view *report.View, categories IssueCategories, top int) *CategoryMatrix {
+	if top > 0 && top < len(categories) {
+		categories = categories[:top]
+	}
+
+	matrix := &CategoryMatrix{Categories: categories, Counts: map[string][]int{}}
+
+	categoryIndex := map[*result.Issue]int{}
+	for idx, category := range categories {
+		for _, issue := range category.Issues {
+			categoryIndex[issue] = idx
+		}
+	}
+
+	for path, subView := range view.SubViews {
+		var (
+			counts = make([]int, len(categories))
+			found  bool
+		)
+
+		for _, linterIssues := range subView.Issues {
+			for _, issue := range linterIssues {
+				if idx, ok := categoryIndex[issue]; ok {
+					counts[idx]++
+					found = true
+				}
+			}
+		}
+
+		if found {
+			matrix.Paths = append(matrix.Paths, path)
+			matrix.Counts[path] = counts
+		}
+	}
+
+	sort.Strings(matrix.Paths)
+
+	return matrix
+}
+
+// IssueRanking groups the issues of the given View into categories of similar issues, ordered from
+// the most to the least frequent one. Each issue belongs to exactly one category, even if it is part
+// of multiple SubViews, and the outcome does not depend on the order in which issues are found.
+//
+// Issues are first grouped by linter and by template, i.e. their text as normalised by the given
+// rules or by the DefaultNormalisationRules if none are specified. Templates are then
+// merged, from the most to the least frequent one, into the closest category whose representative is
+// within the given edit distance, which defaults to 10 when zero. A negative tolerance only groups
+// issues with identical templates. Only distinct templates of a similar length are compared with
+// each other so that the cost of categorisation grows with the variety of issues rather than with
+// their number.
+func IssueRanking(view *report.View, tolerance int, rules *NormalisationRules) IssueCategories {
 	if tolerance == 0 {
 		tolerance = defaultTolerance
 	}
 
-	linterMap := map[string][]*IssueCategory{}
+	if rules == nil {
+		rules = DefaultNormalisationRules()
+	}
+
+	var categories IssueCategories
+	for _, templates := range groupTemplates(collectIssues(view), rules) {
+		categories = append(categories, mergeTemplates(templates, tolerance)...)
+	}
+
+	sort.SliceStable(categories, func(i int, j int) bool {
+		if len(categories[i].Issues) != len(categories[j].Issues) {
+			return len(categories[i].Issues) > len(categories[j].Issues)
+		}
+
+		if categories[i].Linter != categories[j].Linter {
+			return categories[i].Linter < categories[j].Linter
+		}
+
+		return categories[i].Representative < categories[j].Representative
+	})
+
+	return categories
+}
+
+// collectIssues returns each of the issues of the View once, in a stable order.
+func collectIssues(view *report.View) []*result.Issue {
+	var (
+		issues []*result.Issue
+		seen   = map[*result.Issue]bool{}
+	)
 
 	for _, subView := range view.SubViews {
-		for name, issues := range subView.Issues {
-			linterMap[name] = categorise(linterMap[name], issues, tolerance)
+		for _, linterIssues := range subView.Issues {
+			for _, issue := range linterIssues {
+				if !seen[issue] {
+					seen[issue] = true
+					issues = append(issues, issue)
+				}
+			}
 		}
 	}
 
-	var categories []*IssueCategory
-	for _, categoryList := range linterMap {
-		categories = append(categories, categoryList...)
-	}
+	sort.Slice(issues, func(i int, j int) bool { return issueLess(issues[i], issues[j]) })
+
+	return issues
+}
 
-	sort.Slice(categories, func(i int, j int) bool { return len(categories[i].Issues) > len(categories[j].Issues) })
+func issueLess(a *result.Issue, b *result.Issue) bool {
+	switch {
+	case a.FromLinter != b.FromLinter:
+		return a.FromLinter < b.FromLinter
+	case a.Pos.Filename != b.Pos.Filename:
+		return a.Pos.Filename < b.Pos.Filename
+	case a.Pos.Line != b.Pos.Line:
+		return a.Pos.Line < b.Pos.Line
+	case a.Pos.Column != b.Pos.Column:
+		return a.Pos.Column < b.Pos.Column
+	default:
+		return a.Text < b.Text
+	}
+}
 
-	return categories
+// template holds the issues of a single linter that share the same normalised text.
+type template struct {
+	text   string
+	issues []*result.Issue
 }
 
-func categorise(categories []*IssueCategory, issues []*result.Issue, tolerance int) IssueCategories {
+// groupTemplates groups the given issues by linter and then by template. The templates of each linter
+// are ordered from the most to the least frequent one.
+func groupTemplates(issues []*result.Issue, rules *NormalisationRules) [][]*template {
+	var (
+		linters []string
+		byText  = map[string]map[string]*template{}
+	)
+
 	for _, issue := range issues {
-		var categorised bool
+		templates, ok := byText[issue.FromLinter]
+		if !ok {
+			templates = map[string]*template{}
+			byText[issue.FromLinter] = templates
+			linters = append(linters, issue.FromLinter)
+		}
 
-		for _, category := range categories {
-			if levenshtein.ComputeDistance(category.Representative, normalise(issue)) <= tolerance {
-				category.Issues = append(category.Issues, issue)
-				categorised = true
-			}
+		text := rules.Template(issue)
+
+		t, ok := templates[text]
+		if !ok {
+			t = &template{text: text}
+			templates[text] = t
 		}
 
-		if !categorised {
-			categories = append(categories, &IssueCategory{
-				Linter:         issue.FromLinter,
-				Representative: normalise(issue),
-				Issues:         []*result.Issue{issue},
-			})
+		t.issues = append(t.issues, issue)
+	}
+
+	var grouped [][]*template
+
+	for _, linter := range linters {
+		var templates []*template
+		for _, t := range byText[linter] {
+			templates = append(templates, t)
 		}
+
+		sort.Slice(templates, func(i int, j int) bool {
+			if len(templates[i].issues) != len(templates[j].issues) {
+				return len(templates[i].issues) > len(templates[j].issues)
+			}
+
+			return templates[i].text < templates[j].text
+		})
+
+		grouped = append(grouped, templates)
 	}
 
-	return categories
+	return grouped
 }
 
-// We remove string elements between quotes as those tend
-// to be the personalised bits of issue messages.
-func normalise(issue *result.Issue) string {
-	representant := issue.Text
+// mergeTemplates merges the templates of a single linter into categories. Each template joins the
+// category with the closest representative within the tolerance, the earliest one in case of a tie,
+// or otherwise becomes the representative of a new category.
+func mergeTemplates(templates []*template, tolerance int) IssueCategories {
+	var (
+		categories IssueCategories
+		// byLength indexes categories by the length of their representative. As the edit distance
+		// between two strings is at least the difference of their lengths only the categories with a
+		// length within the tolerance need to be considered.
+		byLength = map[int][]*IssueCategory{}
+		index    = map[*IssueCategory]int{}
+	)
 
-	for _, quoteChar := range []string{"\"", "`", "'"} {
-		var parts []string
+	for _, t := range templates {
+		var (
+			closest  *IssueCategory
+			distance = tolerance + 1
+		)
 
-		for idx, element := range strings.Split(representant, quoteChar) {
-			if idx%2 == 1 {
-				parts = append(parts, "<identifier>")
-			} else if len(element) > 0 {
-				parts = append(parts, element)
+		for length := len(t.text) - tolerance; length <= len(t.text)+tolerance; length++ {
+			for _, category := range byLength[length] {
+				d := levenshtein.ComputeDistance(category.Representative, t.text)
+				if d < distance || (d == distance && index[category] < index[closest]) {
+					closest, distance = category, d
+				}
 			}
 		}
 
-		representant = strings.Join(parts, " ")
+		if closest != nil {
+			closest.Issues = append(closest.Issues, t.issues...)
+			continue
+		}
+
+		category := &IssueCategory{
+			Linter:         t.issues[0].FromLinter,
+			Representative: t.text,
+			Issues:         append([]*result.Issue{}, t.issues...),
+		}
+
+		index[category] = len(categories)
+		categories = append(categories, category)
+		byLength[len(t.text)] = append(byLength[len(t.text)], category)
 	}
 
-	return representant
+	return categories
 }
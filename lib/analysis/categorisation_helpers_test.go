@@ -0,0 +1,156 @@
+package analysis
+
+import (
+	"fmt"
+	"go/token"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/golangci/golangci-lint/pkg/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/goality/lib/report"
+)
+
+func Test_IssueRanking(t *testing.T) {
+	newIssue := func(linter string, file string, line int, text string) *result.Issue {
+		return &result.Issue{
+			FromLinter: linter,
+			Text:       text,
+			Pos:        token.Position{Filename: file, Line: line},
+		}
+	}
+
+	var (
+		lllA      = newIssue("lll", "a.go", 1, "line is 130 characters")
+		lllB      = newIssue("lll", "b.go", 2, "line is 121 characters")
+		commentA  = newIssue("golint", "a.go", 3, "exported function `Foo` should have comment or be unexported")
+		commentB  = newIssue("golint", "b.go", 4, "exported method `Bar.Baz` should have comment or be unexported")
+		commentC  = newIssue("golint", "b.go", 5, "exported type `Bar` should have comment or be unexported")
+		receiver  = newIssue("golint", "a.go", 6, "receiver name should be a reflection of its identity")
+		errcheckA = newIssue("errcheck", "a.go", 7, "Error return value of `f.Close` is not checked")
+	)
+
+	// The same issues appear in multiple SubViews, e.g. at different depths.
+	view := &report.View{
+		Linters: []string{"errcheck", "golint", "lll"},
+		SubViews: map[string]*report.SubView{
+			"./...": {Issues: map[string][]*result.Issue{
+				"errcheck": {errcheckA},
+				"golint":   {commentA, receiver, commentB, commentC},
+				"lll":      {lllA, lllB},
+			}},
+			"b.go": {Issues: map[string][]*result.Issue{
+				"golint": {commentB, commentC},
+				"lll":    {lllB},
+			}},
+		},
+	}
+
+	expected := IssueCategories{
+		{
+			Linter:         "golint",
+			Representative: "exported function <identifier> should have comment or be unexported",
+			Issues:         []*result.Issue{commentA, commentB, commentC},
+		},
+		{Linter: "lll", Representative: "line is <number> characters", Issues: []*result.Issue{lllA, lllB}},
+		{
+			Linter:         "errcheck",
+			Representative: "Error return value of <identifier> is not checked",
+			Issues:         []*result.Issue{errcheckA},
+		},
+		{
+			Linter:         "golint",
+			Representative: "receiver name should be a reflection of its identity",
+			Issues:         []*result.Issue{receiver},
+		},
+	}
+
+	assert.Equal(t, expected, IssueRanking(view, 0, nil))
+	assert.Equal(t, "lll: line is <number> characters", expected[1].Key())
+
+	// Without tolerance only identical templates are grouped together.
+	categories := IssueRanking(view, -1, nil)
+	require.Len(t, categories, 6)
+	assert.Equal(t, []*result.Issue{lllA, lllB}, categories[0].Issues)
+}
+
+func Test_CategoryOccurrences(t *testing.T) {
+	var (
+		rootIssue = &result.Issue{Pos: token.Position{Filename: "main.go", Line: 3}}
+		fooFirst  = &result.Issue{Pos: token.Position{Filename: "foo/a.go", Line: 12}}
+		fooSecond = &result.Issue{Pos: token.Position{Filename: "foo/b.go", Line: 1}}
+		barIssue  = &result.Issue{Pos: token.Position{Filename: "foo/bar/c.go", Line: 7}}
+	)
+
+	category := &IssueCategory{Issues: []*result.Issue{fooSecond, barIssue, rootIssue, fooFirst}}
+	assert.Equal(t, []*DirectoryOccurrences{
+		{Directory: ".", Issues: []*result.Issue{rootIssue}},
+		{Directory: "foo", Issues: []*result.Issue{fooFirst, fooSecond}},
+		{Directory: filepath.Join("foo", "bar"), Issues: []*result.Issue{barIssue}},
+	}, category.Occurrences())
+}
+
+func Test_CategoryMatrix(t *testing.T) {
+	var (
+		lllA     = &result.Issue{FromLinter: "lll", Pos: token.Position{Filename: "a/a.go", Line: 1}}
+		lllB     = &result.Issue{FromLinter: "lll", Pos: token.Position{Filename: "b/b.go", Line: 1}}
+		comment  = &result.Issue{FromLinter: "golint", Pos: token.Position{Filename: "a/a.go", Line: 2}}
+		errcheck = &result.Issue{FromLinter: "errcheck", Pos: token.Position{Filename: "c/c.go", Line: 3}}
+	)
+
+	view := &report.View{
+		SubViews: map[string]*report.SubView{
+			".":     {Path: "."},
+			"a/...": {Path: "a/...", Issues: map[string][]*result.Issue{"lll": {lllA}, "golint": {comment}}},
+			"b/...": {Path: "b/...", Issues: map[string][]*result.Issue{"lll": {lllB}}},
+			"c/...": {Path: "c/...", Issues: map[string][]*result.Issue{"errcheck": {errcheck}}},
+		},
+	}
+
+	categories := IssueCategories{
+		{Linter: "lll", Issues: []*result.Issue{lllA, lllB}},
+		{Linter: "golint", Issues: []*result.Issue{comment}},
+		{Linter: "errcheck", Issues: []*result.Issue{errcheck}},
+	}
+
+	assert.Equal(t, &CategoryMatrix{
+		Categories: categories[:2],
+		Paths:      []string{"a/...", "b/..."},
+		Counts: map[string][]int{
+			"a/...": {1, 1},
+			"b/...": {1, 0},
+		},
+	}, NewCategoryMatrix(view, categories, 2))
+
+	matrix := NewCategoryMatrix(view, categories, 0)
+	assert.Equal(t, []string{"a/...", "b/...", "c/..."}, matrix.Paths)
+	assert.Equal(t, []int{0, 0, 1}, matrix.Counts["c/..."])
+}
+
+func Test_IssueRankingStable(t *testing.T) {
+	var issues []*result.Issue
+	for i := 0; i < 20000; i++ {
+		issues = append(issues, &result.Issue{
+			FromLinter: "golint",
+			Text:       fmt.Sprintf("exported %s `Foo%d` should have comment or be unexported", []string{"function", "method", "type", "const"}[i%4], i),
+			Pos:        token.Position{Filename: fmt.Sprintf("file%d.go", i%100), Line: i},
+		})
+	}
+
+	rankIssues := func(issues []*result.Issue) IssueCategories {
+		shuffled := append([]*result.Issue{}, issues...)
+		rand.Shuffle(len(shuffled), func(i int, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+		return IssueRanking(&report.View{
+			SubViews: map[string]*report.SubView{"./...": {Issues: map[string][]*result.Issue{"golint": shuffled}}},
+		}, 0, nil)
+	}
+
+	categories := rankIssues(issues)
+	require.Len(t, categories, 1)
+	assert.Len(t, categories[0].Issues, len(issues))
+	assert.Equal(t, categories, rankIssues(issues))
+}
@@ -0,0 +1,239 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golangci/golangci-lint/pkg/result"
+	"gopkg.in/yaml.v2"
+
+	"github.com/Helcaraxan/goality/lib/report"
+)
+
+// EffortModel estimates the time required to fix issues, e.g.:
+//
+//	default: 10m
+//	line-cost: 30m
+//	linters:
+//	  errcheck: 5m
+//	  gocyclo: 1h
+//	categories:
+//	  - linter: golint
+//	    text: "should have comment"
+//	    effort: 2m
+//
+// The effort of an issue is that of the first category whose linter, if specified, and text regular
+// expression match the issue's template, i.e. its text as normalised by the model's normalisation
+// rules. Issues outside of any category have the effort of their linter or, failing that, the
+// default effort.
+type EffortModel struct {
+	Default    time.Duration            `yaml:"default"`
+	Linters    map[string]time.Duration `yaml:"linters"`
+	Categories []*EffortCategory        `yaml:"categories"`
+	// LineCost is the estimated time required to develop a single line of code. It is the basis of the
+	// debt ratio, which relates the remediation effort to the development cost of the code.
+	LineCost time.Duration `yaml:"line-cost"`
+	// Normalisation holds the rules that turn the text of issues into the templates that are matched
+	// against the categories. It should be the same as the one used to categorise issues. The
+	// DefaultNormalisationRules are used if it is nil.
+	Normalisation *NormalisationRules `yaml:"-"`
+}
+
+// EffortCategory assigns an effort to the issues of an IssueCategory.
+type EffortCategory struct {
+	Linter string        `yaml:"linter"`
+	Text   string        `yaml:"text"`
+	Effort time.Duration `yaml:"effort"`
+
+	text *regexp.Regexp
+}
+
+// DefaultEffortModel returns the EffortModel in which each issue takes 10 minutes to fix and each line
+// of code takes 30 minutes to develop.
+func DefaultEffortModel() *EffortModel {
+	return &EffortModel{
+		Default:  10 * time.Minute,
+		LineCost: 30 * time.Minute,
+	}
+}
+
+// LoadEffortModel reads an EffortModel from the given YAML file. Settings that are not present in the
+// file keep the value of the DefaultEffortModel.
+func LoadEffortModel(path string) (*EffortModel, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	model := DefaultEffortModel()
+	if err = yaml.UnmarshalStrict(content, model); err != nil {
+		return nil, fmt.Errorf("could not parse effort model from %q: %v", path, err)
+	}
+
+	if err = model.validate(); err != nil {
+		return nil, fmt.Errorf("invalid effort model in %q: %v", path, err)
+	}
+
+	return model, nil
+}
+
+func (m *EffortModel) validate() error {
+	if m.LineCost <= 0 {
+		return errors.New("the line cost must be strictly positive")
+	}
+
+	if m.Default < 0 {
+		return errors.New("the default effort must not be negative")
+	}
+
+	for linter, effort := range m.Linters {
+		if effort < 0 {
+			return fmt.Errorf("the effort of %q must not be negative", linter)
+		}
+	}
+
+	for idx, category := range m.Categories {
+		if category.Text == "" {
+			return fmt.Errorf("category #%d does not specify a 'text' regular expression", idx+1)
+		} else if category.Effort < 0 {
+			return fmt.Errorf("the effort of category #%d must not be negative", idx+1)
+		}
+
+		var err error
+		if category.text, err = regexp.Compile(category.Text); err != nil {
+			return fmt.Errorf("invalid regular expression in category #%d: %v", idx+1, err)
+		}
+	}
+
+	return nil
+}
+
+// Effort returns the estimated time required to fix the given issue.
+func (m *EffortModel) Effort(issue *result.Issue) time.Duration {
+	rules := m.Normalisation
+	if rules == nil {
+		rules = DefaultNormalisationRules()
+	}
+
+	if category := m.category(issue.FromLinter, rules.Template(issue)); category != nil {
+		return category.Effort
+	}
+
+	if effort, ok := m.Linters[issue.FromLinter]; ok {
+		return effort
+	}
+
+	return m.Default
+}
+
+func (m *EffortModel) category(linter string, text string) *EffortCategory {
+	for _, category := range m.Categories {
+		if (category.Linter == "" || category.Linter == linter) && category.text.MatchString(text) {
+			return category
+		}
+	}
+
+	return nil
+}
+
+// CategoryEffort returns the estimated time required to fix all the issues of the given category.
+func (m *EffortModel) CategoryEffort(category *IssueCategory) time.Duration {
+	var effort time.Duration
+	for _, issue := range category.Issues {
+		effort += m.Effort(issue)
+	}
+
+	return effort
+}
+
+// Remediation is the outcome of applying an EffortModel to a SubView.
+type Remediation struct {
+	Effort time.Duration
+	// DebtRatio is the remediation effort relative to the estimated cost of developing the SubView's
+	// code. It is zero for SubViews without any lines.
+	DebtRatio float64
+	// PerLinter holds the part of the Effort that originates from each linter with issues.
+	PerLinter map[string]time.Duration
+}
+
+// Remediation computes the effort required to fix the issues of the given SubView, which belongs to
+// the given View.
+func (m *EffortModel) Remediation(view *report.View, subView *report.SubView) *Remediation {
+	remediation := &Remediation{}
+
+	for _, linter := range view.Linters {
+		var effort time.Duration
+		for _, issue := range subView.Issues[linter] {
+			effort += m.Effort(issue)
+		}
+
+		if effort == 0 {
+			continue
+		}
+
+		if remediation.PerLinter == nil {
+			remediation.PerLinter = map[string]time.Duration{}
+		}
+
+		remediation.PerLinter[linter] = effort
+		remediation.Effort += effort
+	}
+
+	if lines := subView.Lines(view.RateMetric); lines > 0 {
+		remediation.DebtRatio = float64(remediation.Effort) / (float64(lines) * float64(m.LineCost))
+	}
+
+	return remediation
+}
+
+// Description documents how remediation efforts are estimated with this model, for the given rate
+// metric.
+func (m *EffortModel) Description(metric report.LineMetric) string {
+	var linters []string
+	for linter := range m.Linters {
+		linters = append(linters, linter)
+	}
+
+	sort.Strings(linters)
+
+	efforts := []string{"default " + FormatEffort(m.Default)}
+	for _, linter := range linters {
+		efforts = append(efforts, fmt.Sprintf("%s %s", linter, FormatEffort(m.Linters[linter])))
+	}
+
+	description := []string{
+		fmt.Sprintf("Remediation: effort (debt-ratio = effort / (%s x %s))", metric, FormatEffort(m.LineCost)),
+		"Efforts: " + strings.Join(efforts, ", "),
+	}
+
+	if len(m.Categories) > 0 {
+		var categories []string
+		for _, category := range m.Categories {
+			name := fmt.Sprintf("%q", category.Text)
+			if category.Linter != "" {
+				name = category.Linter + " " + name
+			}
+
+			categories = append(categories, fmt.Sprintf("%s %s", name, FormatEffort(category.Effort)))
+		}
+
+		description = append(description, "Category efforts: "+strings.Join(categories, ", "))
+	}
+
+	return strings.Join(description, "\n")
+}
+
+// FormatEffort prints the given effort in hours and minutes, e.g. '3h05m' or '45m'.
+func FormatEffort(effort time.Duration) string {
+	minutes := int64(effort.Round(time.Minute) / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
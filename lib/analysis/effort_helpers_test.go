@@ -0,0 +1,134 @@
+package analysis
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golangci/golangci-lint/pkg/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/goality/lib/report"
+)
+
+func Test_Remediation(t *testing.T) {
+	modelFile := writeTestModel(t, `default: 15m
+line-cost: 1h
+linters:
+  errcheck: 5m
+categories:
+  - linter: golint
+    text: "should have comment"
+    effort: 2m
+  - text: "^line is <number> characters"
+    effort: 1m
+`)
+	defer os.Remove(modelFile)
+
+	model, err := LoadEffortModel(modelFile)
+	require.NoError(t, err)
+
+	var (
+		errcheckIssue = &result.Issue{FromLinter: "errcheck", Text: "Error return value of `f.Close` is not checked"}
+		commentIssue  = &result.Issue{FromLinter: "golint", Text: "exported function Foo should have comment or be unexported"}
+		namingIssue   = &result.Issue{FromLinter: "golint", Text: "don't use underscores in Go names"}
+		lllIssue      = &result.Issue{FromLinter: "lll", Text: "line is 130 characters"}
+	)
+
+	assert.Equal(t, 5*time.Minute, model.Effort(errcheckIssue))
+	assert.Equal(t, 2*time.Minute, model.Effort(commentIssue))
+	assert.Equal(t, 15*time.Minute, model.Effort(namingIssue))
+	assert.Equal(t, time.Minute, model.Effort(lllIssue))
+
+	view := &report.View{Linters: []string{"errcheck", "golint", "lll"}}
+	subView := &report.SubView{
+		LineCount: 60,
+		Issues: map[string][]*result.Issue{
+			"errcheck": {errcheckIssue, errcheckIssue},
+			"golint":   {commentIssue, namingIssue},
+		},
+	}
+
+	assert.Equal(t, &Remediation{
+		Effort:    27 * time.Minute,
+		DebtRatio: 27.0 / 3600,
+		PerLinter: map[string]time.Duration{
+			"errcheck": 10 * time.Minute,
+			"golint":   17 * time.Minute,
+		},
+	}, model.Remediation(view, subView))
+
+	assert.Equal(t, &Remediation{}, model.Remediation(view, &report.SubView{}))
+
+	category := &IssueCategory{Linter: "golint", Issues: []*result.Issue{commentIssue, commentIssue, commentIssue}}
+	assert.Equal(t, 6*time.Minute, model.CategoryEffort(category))
+
+	expectedDescription := `Remediation: effort (debt-ratio = effort / (LoC x 1h00m))
+Efforts: default 15m, errcheck 5m
+Category efforts: golint "should have comment" 2m, "^line is <number> characters" 1m`
+	assert.Equal(t, expectedDescription, model.Description(report.LineMetricCode))
+}
+
+func Test_EffortNormalisation(t *testing.T) {
+	rulesFile := writeTestModel(t, `rules:
+  - linter: mylinter
+    pattern: "took [0-9]+ms"
+    replacement: "took <duration>"
+`)
+	defer os.Remove(rulesFile)
+
+	rules, err := LoadNormalisationRules(rulesFile)
+	require.NoError(t, err)
+
+	model := &EffortModel{
+		Default:  10 * time.Minute,
+		LineCost: 30 * time.Minute,
+		Categories: []*EffortCategory{
+			{Linter: "mylinter", Text: "^check took <duration>$", Effort: time.Minute},
+		},
+	}
+	require.NoError(t, model.validate())
+
+	issue := &result.Issue{FromLinter: "mylinter", Text: "check took 12ms"}
+	assert.Equal(t, 10*time.Minute, model.Effort(issue))
+
+	model.Normalisation = rules
+	assert.Equal(t, time.Minute, model.Effort(issue))
+
+	category := &IssueCategory{Linter: "mylinter", Issues: []*result.Issue{issue, issue}}
+	assert.Equal(t, 2*time.Minute, model.CategoryEffort(category))
+}
+
+func Test_LoadEffortModel(t *testing.T) {
+	modelFile := writeTestModel(t, "linters:\n  errcheck: 5m\n")
+	defer os.Remove(modelFile)
+
+	model, err := LoadEffortModel(modelFile)
+	require.NoError(t, err)
+	assert.Equal(t, &EffortModel{
+		Default:  10 * time.Minute,
+		Linters:  map[string]time.Duration{"errcheck": 5 * time.Minute},
+		LineCost: 30 * time.Minute,
+	}, model)
+
+	for name, content := range map[string]string{
+		"UnknownField":    "linter:\n  errcheck: 5m\n",
+		"InvalidDuration": "default: ten minutes\n",
+		"NegativeEffort":  "linters:\n  errcheck: -5m\n",
+		"NoLineCost":      "line-cost: 0s\n",
+		"NoCategoryText":  "categories:\n  - linter: golint\n    effort: 2m\n",
+		"InvalidRegexp":   "categories:\n  - text: '[foo'\n    effort: 2m\n",
+	} {
+		invalidFile := writeTestModel(t, content)
+		_, err = LoadEffortModel(invalidFile)
+		assert.Error(t, err, "Should not accept an effort model with %s.", name)
+		_ = os.Remove(invalidFile)
+	}
+}
+
+func Test_FormatEffort(t *testing.T) {
+	assert.Equal(t, "0m", FormatEffort(0))
+	assert.Equal(t, "45m", FormatEffort(45*time.Minute+10*time.Second))
+	assert.Equal(t, "3h05m", FormatEffort(3*time.Hour+5*time.Minute))
+}
@@ -0,0 +1,97 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Helcaraxan/goality/lib/report"
+)
+
+type Hotspots []*Hotspot
+
+func (h Hotspots) String() string {
+	var output []string
+	for idx := range h {
+		output = append(output, h[idx].String())
+	}
+
+	return strings.Join(output, "\n")
+}
+
+// Hotspot is a part of a project that is both frequently changed and has a high issue rate. Such code
+// is where improvements in quality have the most impact.
+type Hotspot struct {
+	Path string
+	// Score ranges from 0 to 100 and is the product of the churn and of the issue rate, each of which
+	// is relative to the highest value among all the View's SubViews.
+	Score        float32
+	Commits      int
+	ChangedLines int
+	LastModified time.Time
+	Issues       int
+	IssueRate    float32
+}
+
+func (h *Hotspot) String() string {
+	return fmt.Sprintf("%s - score %.1f - %d commits - %.2f issues / 1k lines", h.Path, h.Score, h.Commits, h.IssueRate)
+}
+
+// HotspotRanking returns the SubViews of the given View that have both churn and issues, ordered from
+// the highest to the lowest hotspot score.
+func HotspotRanking(view *report.View) Hotspots {
+	var (
+		hotspots   Hotspots
+		maxCommits int
+		maxRate    float32
+	)
+
+	for _, subView := range view.SubViews {
+		if subView.Churn == nil {
+			continue
+		}
+
+		var issueCount int
+		for _, linter := range view.Linters {
+			issueCount += len(subView.Issues[linter])
+		}
+
+		hotspot := &Hotspot{
+			Path:         subView.Path,
+			Commits:      subView.Churn.Commits,
+			ChangedLines: subView.Churn.Lines(),
+			LastModified: subView.Churn.LastModified,
+			Issues:       issueCount,
+			IssueRate:    subView.OccurrenceRate(issueCount, view.RateMetric),
+		}
+
+		if hotspot.Commits == 0 || hotspot.Issues == 0 {
+			continue
+		}
+
+		if hotspot.Commits > maxCommits {
+			maxCommits = hotspot.Commits
+		}
+
+		if hotspot.IssueRate > maxRate {
+			maxRate = hotspot.IssueRate
+		}
+
+		hotspots = append(hotspots, hotspot)
+	}
+
+	for _, hotspot := range hotspots {
+		hotspot.Score = 100 * float32(hotspot.Commits) / float32(maxCommits) * hotspot.IssueRate / maxRate
+	}
+
+	sort.Slice(hotspots, func(i int, j int) bool {
+		if hotspots[i].Score != hotspots[j].Score {
+			return hotspots[i].Score > hotspots[j].Score
+		}
+
+		return hotspots[i].Path < hotspots[j].Path
+	})
+
+	return hotspots
+}
@@ -0,0 +1,118 @@
+package analysis
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/golangci/golangci-lint/pkg/result"
+	"gopkg.in/yaml.v2"
+)
+
+// NormalisationRules turn the text of issues into templates by masking the parts that are specific to
+// each occurrence, such as identifiers, types, numbers and file paths. Issues with the same template
+// are considered to be of the same category.
+//
+// The rules are applied in order: first those that were loaded from a configuration file, then the
+// built-in rules for specific linters and finally the generic ones that apply to all linters. Each
+// rule replaces all matches of its pattern, which can refer to capture groups via '$1', etc.
+type NormalisationRules struct {
+	Rules []*NormalisationRule `yaml:"rules"`
+}
+
+// NormalisationRule replaces the text matching a regular expression in the issues of a linter, or of
+// all linters if none is specified.
+type NormalisationRule struct {
+	Linter      string `yaml:"linter"`
+	Pattern     string `yaml:"pattern"`
+	Replacement string `yaml:"replacement"`
+
+	pattern *regexp.Regexp
+}
+
+// builtinNormalisationRules handles the messages of common golangci-lint linters that contain
+// unquoted identifiers or types.
+var builtinNormalisationRules = []*NormalisationRule{
+	{Linter: "golint", Pattern: `^exported (\w+) \S+ should have comment`, Replacement: "exported $1 <identifier> should have comment"},
+	{Linter: "golint", Pattern: `^comment on exported (\w+) \S+ should be of the form .*$`, Replacement: "comment on exported $1 <identifier> should be of the form <comment>"},
+	{Linter: "golint", Pattern: `\b((?:struct field|method parameter|func parameter|func result|range var|var|const|func|type|method)) \S+ should be \S+$`, Replacement: "$1 <identifier> should be <identifier>"},
+	{Linter: "golint", Pattern: `^receiver name \S+ should be consistent with previous receiver name \S+ for \S+$`, Replacement: "receiver name <identifier> should be consistent with previous receiver name <identifier> for <type>"},
+	{Linter: "golint", Pattern: `^type name will be used as \S+ by other packages, and that stutters; consider calling this \S+$`, Replacement: "type name will be used as <identifier> by other packages, and that stutters; consider calling this <identifier>"},
+	{Linter: "stylecheck", Pattern: `\b((?:struct field|method parameter|func parameter|func result|range var|var|const|func|type|method)) \S+ should be \S+$`, Replacement: "$1 <identifier> should be <identifier>"},
+	{Linter: "stylecheck", Pattern: `^(ST1016): methods on the same type should have the same receiver name .*$`, Replacement: "$1: methods on the same type should have the same receiver name <details>"},
+	{Linter: "staticcheck", Pattern: `^(SA1019): \S+ is deprecated.*$`, Replacement: "$1: <identifier> is deprecated"},
+	{Linter: "typecheck", Pattern: `^(undeclared name|undefined): \S+$`, Replacement: "$1: <identifier>"},
+	{Linter: "typecheck", Pattern: `^\S+ declared but not used$`, Replacement: "<identifier> declared but not used"},
+	{Linter: "typecheck", Pattern: `^cannot use \S+ \((variable|value|constant) of type [^)]+\) as \S+ value`, Replacement: "cannot use <identifier> ($1 of type <type>) as <type> value"},
+	{Linter: "govet", Pattern: `has arg \S+ of wrong type \S+`, Replacement: "has arg <identifier> of wrong type <type>"},
+	{Linter: "govet", Pattern: `^composites: \S+ composite literal`, Replacement: "composites: <type> composite literal"},
+	{Linter: "unparam", Pattern: `- result ([0-9]+) \(\S+\) is`, Replacement: "- result $1 (<type>) is"},
+	{Linter: "dupl", Pattern: `^[0-9]+-[0-9]+ lines are duplicate of .*$`, Replacement: "<number>-<number> lines are duplicate of <path>"},
+}
+
+// genericNormalisationRules mask quoted text, file paths and numbers in the messages of all linters.
+var genericNormalisationRules = []*NormalisationRule{
+	{Pattern: `(?:[\w.-]+/)*[\w.-]+\.go(?::[0-9]+)*`, Replacement: "<path>"},
+	{Pattern: "`[^`]*`", Replacement: "<identifier>"},
+	{Pattern: `"(?:[^"\\]|\\.)*"`, Replacement: "<identifier>"},
+	{Pattern: `'[^'\s]+'`, Replacement: "<identifier>"},
+	{Pattern: `\b(?:0x[0-9a-fA-F]+|[0-9]+(?:\.[0-9]+)?)\b`, Replacement: "<number>"},
+	{Pattern: `\s+`, Replacement: " "},
+}
+
+func init() {
+	for _, rule := range append(append([]*NormalisationRule{}, builtinNormalisationRules...), genericNormalisationRules...) {
+		rule.pattern = regexp.MustCompile(rule.Pattern)
+	}
+}
+
+// DefaultNormalisationRules returns the NormalisationRules that only consist of the built-in rules.
+func DefaultNormalisationRules() *NormalisationRules {
+	return &NormalisationRules{}
+}
+
+// LoadNormalisationRules reads additional normalisation rules from the given YAML file, e.g.:
+//
+//	rules:
+//	  - linter: mylinter
+//	    pattern: "took [0-9]+ms"
+//	    replacement: "took <duration>"
+func LoadNormalisationRules(path string) (*NormalisationRules, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := &NormalisationRules{}
+	if err = yaml.UnmarshalStrict(content, rules); err != nil {
+		return nil, fmt.Errorf("could not parse normalisation rules from %q: %v", path, err)
+	}
+
+	for idx, rule := range rules.Rules {
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("normalisation rule #%d in %q does not specify a 'pattern'", idx+1, path)
+		}
+
+		if rule.pattern, err = regexp.Compile(rule.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid regular expression in normalisation rule #%d in %q: %v", idx+1, path, err)
+		}
+	}
+
+	return rules, nil
+}
+
+// Template returns the normalised text of the given issue.
+func (r *NormalisationRules) Template(issue *result.Issue) string {
+	text := issue.Text
+
+	for _, rules := range [][]*NormalisationRule{r.Rules, builtinNormalisationRules, genericNormalisationRules} {
+		for _, rule := range rules {
+			if rule.Linter == "" || rule.Linter == issue.FromLinter {
+				text = rule.pattern.ReplaceAllString(text, rule.Replacement)
+			}
+		}
+	}
+
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,101 @@
+package analysis
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golangci/golangci-lint/pkg/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DefaultNormalisationRules(t *testing.T) {
+	testcases := map[string]struct {
+		linter   string
+		text     string
+		expected string
+	}{
+		"Plain":       {text: "should have comment", expected: "should have comment"},
+		"Backquotes":  {text: "func `unusedFunc` is unused", expected: "func <identifier> is unused"},
+		"Quotes":      {text: `string "foo" has 3 occurrences`, expected: "string <identifier> has <number> occurrences"},
+		"Numbers":     {text: "line is 130 characters", expected: "line is <number> characters"},
+		"Decimals":    {text: "complexity 12.5 is too high", expected: "complexity <number> is too high"},
+		"Identifiers": {text: "var2 is unused", expected: "var2 is unused"},
+		"Paths":       {text: "File is not `gofmt`-ed with `-s` (see lib/report/parse.go:12)", expected: "File is not <identifier>-ed with <identifier> (see <path>)"},
+		"Whitespace":  {text: "  too   many spaces ", expected: "too many spaces"},
+		"GolintComment": {
+			linter:   "golint",
+			text:     "exported method Bar.Baz should have comment or be unexported",
+			expected: "exported method <identifier> should have comment or be unexported",
+		},
+		"GolintNaming": {
+			linter:   "golint",
+			text:     "func parameter userId should be userID",
+			expected: "func parameter <identifier> should be <identifier>",
+		},
+		"OtherLinterNaming": {
+			linter:   "misspell",
+			text:     "func parameter userId should be userID",
+			expected: "func parameter userId should be userID",
+		},
+		"Gocyclo": {
+			linter:   "gocyclo",
+			text:     "cyclomatic complexity 31 of func `Parse` is high (> 30)",
+			expected: "cyclomatic complexity <number> of func <identifier> is high (> <number>)",
+		},
+		"Staticcheck": {
+			linter:   "staticcheck",
+			text:     "SA1019: ioutil.ReadFile is deprecated: As of Go 1.16, this function simply calls os.ReadFile.",
+			expected: "SA1019: <identifier> is deprecated",
+		},
+		"Typecheck": {
+			linter:   "typecheck",
+			text:     "cannot use x (variable of type int) as string value in argument to f",
+			expected: "cannot use <identifier> (variable of type <type>) as <type> value in argument to f",
+		},
+		"Dupl": {
+			linter:   "dupl",
+			text:     "12-30 lines are duplicate of `lib/report/report.go:40-58`",
+			expected: "<number>-<number> lines are duplicate of <path>",
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			issue := &result.Issue{FromLinter: testcase.linter, Text: testcase.text}
+			assert.Equal(t, testcase.expected, DefaultNormalisationRules().Template(issue))
+		})
+	}
+}
+
+func Test_LoadNormalisationRules(t *testing.T) {
+	rulesFile := writeTestModel(t, `rules:
+  - linter: mylinter
+    pattern: "took [0-9]+ms"
+    replacement: "took <duration>"
+  - pattern: "^TODO\\((\\w+)\\):"
+    replacement: "TODO(<author>):"
+`)
+	defer os.Remove(rulesFile)
+
+	rules, err := LoadNormalisationRules(rulesFile)
+	require.NoError(t, err)
+	require.Len(t, rules.Rules, 2)
+
+	// User rules apply before the built-in ones.
+	assert.Equal(t, "check took <duration>", rules.Template(&result.Issue{FromLinter: "mylinter", Text: "check took 12ms"}))
+	assert.Equal(t, "check took 12ms", rules.Template(&result.Issue{FromLinter: "other", Text: "check took 12ms"}))
+	assert.Equal(t, "TODO(<author>): fix this", rules.Template(&result.Issue{FromLinter: "godox", Text: "TODO(jane): fix this"}))
+
+	for name, content := range map[string]string{
+		"UnknownField":  "rule:\n  - pattern: foo\n",
+		"NoPattern":     "rules:\n  - linter: golint\n    replacement: foo\n",
+		"InvalidRegexp": "rules:\n  - pattern: '[foo'\n",
+	} {
+		invalidFile := writeTestModel(t, content)
+		_, err = LoadNormalisationRules(invalidFile)
+		assert.Error(t, err, "Should not accept normalisation rules with %s.", name)
+		_ = os.Remove(invalidFile)
+	}
+}
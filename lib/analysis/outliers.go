@@ -0,0 +1,229 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Helcaraxan/goality/lib/report"
+)
+
+// AllLinters is the linter of the Outliers that are based on the issues of all linters combined.
+const AllLinters = "all"
+
+// minPeers is the minimum number of SubViews in a size class for its members to be compared only with
+// each other. Smaller size classes are compared with all SubViews instead.
+const minPeers = 5
+
+// OutlierCriteria determine which issue rates are considered to be outliers. A rate is an outlier if
+// it is above the mean of its peers and exceeds any of the enabled thresholds.
+type OutlierCriteria struct {
+	// ZScore is the number of standard deviations above the mean of its peers beyond which a rate is
+	// an outlier. Disabled when zero.
+	ZScore float64
+	// Percentile is the percentage of peers, ranging from 0 to 100, that must have a lower rate for a
+	// rate to be an outlier. Disabled when zero.
+	Percentile float64
+	// MinLines is the number of lines below which SubViews are ignored as their issue rates are not
+	// meaningful.
+	MinLines int
+}
+
+// DefaultOutlierCriteria returns the OutlierCriteria that flag rates that are more than two standard
+// deviations above the mean of SubViews of at least 50 lines.
+func DefaultOutlierCriteria() *OutlierCriteria {
+	return &OutlierCriteria{ZScore: 2, MinLines: 50}
+}
+
+func (c *OutlierCriteria) String() string {
+	var thresholds []string
+	if c.ZScore > 0 {
+		thresholds = append(thresholds, fmt.Sprintf("z-score above %.1f", c.ZScore))
+	}
+
+	if c.Percentile > 0 {
+		thresholds = append(thresholds, fmt.Sprintf("percentile of at least %.0f", c.Percentile))
+	}
+
+	return fmt.Sprintf("%s among peers of comparable size with at least %d lines", strings.Join(thresholds, " or "), c.MinLines)
+}
+
+type Outliers []*Outlier
+
+func (o Outliers) String() string {
+	var output []string
+	for idx := range o {
+		output = append(output, o[idx].String())
+	}
+
+	return strings.Join(output, "\n")
+}
+
+// Outlier is a SubView whose issue rate for a linter, or for AllLinters, is far above the one of the
+// SubViews of comparable size.
+type Outlier struct {
+	Path   string
+	Linter string
+	Lines  int
+	Issues int
+	Rate   float32
+	// Peers is the number of other SubViews to which the rate was compared. PeerMean and PeerStdDev are
+	// the mean and standard deviation of their rates.
+	Peers      int
+	PeerMean   float64
+	PeerStdDev float64
+	// ZScore is infinite if all peers have the same rate.
+	ZScore float64
+	// Percentile is the percentage of the peers that have a lower rate.
+	Percentile float64
+}
+
+func (o *Outlier) String() string {
+	return fmt.Sprintf("%s - %s - %.2f issues / 1k lines - z-score %.1f - percentile %.0f", o.Path, o.Linter, o.Rate, o.ZScore, o.Percentile)
+}
+
+// OutlierRanking compares the issue rates, per linter and for all linters combined, of the Packages of
+// the given View, or of its SubViews if package metrics were not computed. Each SubView is compared
+// with its peers: the SubViews of the same size class, i.e. with a number of lines of the same order
+// of magnitude. The rates that meet the given criteria, which default to the DefaultOutlierCriteria,
+// are returned from the highest to the lowest z-score.
+func OutlierRanking(view *report.View, criteria *OutlierCriteria) Outliers {
+	if criteria == nil {
+		criteria = DefaultOutlierCriteria()
+	}
+
+	subViews := view.Packages
+	if subViews == nil {
+		for _, subView := range view.SubViews {
+			subViews = append(subViews, subView)
+		}
+	}
+
+	var candidates []*report.SubView
+	for _, subView := range subViews {
+		if lines := subView.Lines(view.RateMetric); lines > 0 && lines >= criteria.MinLines {
+			candidates = append(candidates, subView)
+		}
+	}
+
+	sizeClasses := map[int][]*report.SubView{}
+	for _, subView := range candidates {
+		sizeClass := sizeClass(subView.Lines(view.RateMetric))
+		sizeClasses[sizeClass] = append(sizeClasses[sizeClass], subView)
+	}
+
+	var outliers Outliers
+	for _, subView := range candidates {
+		peers := sizeClasses[sizeClass(subView.Lines(view.RateMetric))]
+		if len(peers) < minPeers {
+			peers = candidates
+		}
+
+		for _, linter := range append([]string{AllLinters}, view.Linters...) {
+			if outlier := newOutlier(view, subView, peers, linter, criteria); outlier != nil {
+				outliers = append(outliers, outlier)
+			}
+		}
+	}
+
+	sort.Slice(outliers, func(i int, j int) bool {
+		if outliers[i].ZScore != outliers[j].ZScore {
+			return outliers[i].ZScore > outliers[j].ZScore
+		}
+
+		if outliers[i].Path != outliers[j].Path {
+			return outliers[i].Path < outliers[j].Path
+		}
+
+		return outliers[i].Linter < outliers[j].Linter
+	})
+
+	return outliers
+}
+
+// sizeClass returns the order of magnitude of the given number of lines.
+func sizeClass(lines int) int {
+	return int(math.Floor(math.Log10(float64(lines))))
+}
+
+func newOutlier(view *report.View, subView *report.SubView, peers []*report.SubView, linter string, criteria *OutlierCriteria) *Outlier {
+	// The rate is compared with those of the other peers only, as including it in their statistics
+	// would bound its z-score by the square root of their number.
+	var others []float64
+	for _, peer := range peers {
+		if peer != subView {
+			others = append(others, float64(issueRate(view, peer, linter)))
+		}
+	}
+
+	if len(others) < 2 {
+		return nil
+	}
+
+	var (
+		rate  = issueRate(view, subView, linter)
+		sum   float64
+		lower int
+	)
+
+	for _, peerRate := range others {
+		sum += peerRate
+
+		if peerRate < float64(rate) {
+			lower++
+		}
+	}
+
+	mean := sum / float64(len(others))
+	if float64(rate) <= mean {
+		return nil
+	}
+
+	var squares float64
+	for _, peerRate := range others {
+		squares += math.Pow(peerRate-mean, 2)
+	}
+
+	outlier := &Outlier{
+		Path:       subView.Path,
+		Linter:     linter,
+		Lines:      subView.Lines(view.RateMetric),
+		Issues:     issueCount(view, subView, linter),
+		Rate:       rate,
+		Peers:      len(others),
+		PeerMean:   mean,
+		PeerStdDev: math.Sqrt(squares / float64(len(others))),
+		Percentile: 100 * float64(lower) / float64(len(others)),
+	}
+
+	if outlier.PeerStdDev > 0 {
+		outlier.ZScore = (float64(rate) - mean) / outlier.PeerStdDev
+	} else {
+		// All other peers share the same rate, which this one exceeds.
+		outlier.ZScore = math.Inf(1)
+	}
+
+	if (criteria.ZScore > 0 && outlier.ZScore > criteria.ZScore) || (criteria.Percentile > 0 && outlier.Percentile >= criteria.Percentile) {
+		return outlier
+	}
+
+	return nil
+}
+
+func issueCount(view *report.View, subView *report.SubView, linter string) int {
+	if linter != AllLinters {
+		return len(subView.Issues[linter])
+	}
+
+	var count int
+	for _, linter := range view.Linters {
+		count += len(subView.Issues[linter])
+	}
+
+	return count
+}
+
+func issueRate(view *report.View, subView *report.SubView, linter string) float32 {
+	return subView.OccurrenceRate(issueCount(view, subView, linter), view.RateMetric)
+}
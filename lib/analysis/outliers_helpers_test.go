@@ -0,0 +1,125 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golangci/golangci-lint/pkg/result"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Helcaraxan/goality/lib/report"
+)
+
+func Test_OutlierRanking(t *testing.T) {
+	newSubView := func(path string, lines int, issueCount int) *report.SubView {
+		subView := &report.SubView{Path: path, LineCount: lines, Issues: map[string][]*result.Issue{}}
+		for i := 0; i < issueCount; i++ {
+			subView.Issues["golint"] = append(subView.Issues["golint"], &result.Issue{FromLinter: "golint"})
+		}
+
+		return subView
+	}
+
+	view := &report.View{
+		Linters: []string{"golint", "lll"},
+		SubViews: map[string]*report.SubView{
+			"a": newSubView("a", 100, 1),
+			"b": newSubView("b", 200, 4),
+			"c": newSubView("c", 300, 3),
+			"d": newSubView("d", 400, 8),
+			"e": newSubView("e", 500, 5),
+			"f": newSubView("f", 100, 10),
+			// Too small to be taken into account.
+			"tiny": newSubView("tiny", 10, 5),
+			// The only one in its size class, so compared with all other SubViews.
+			"large": newSubView("large", 2000, 2),
+		},
+	}
+
+	stdDev := math.Sqrt(24)
+	expected := Outliers{
+		{
+			Path:       "f",
+			Linter:     AllLinters,
+			Lines:      100,
+			Issues:     10,
+			Rate:       100,
+			Peers:      5,
+			PeerMean:   14,
+			PeerStdDev: stdDev,
+			ZScore:     86 / stdDev,
+			Percentile: 100,
+		},
+		{
+			Path:       "f",
+			Linter:     "golint",
+			Lines:      100,
+			Issues:     10,
+			Rate:       100,
+			Peers:      5,
+			PeerMean:   14,
+			PeerStdDev: stdDev,
+			ZScore:     86 / stdDev,
+			Percentile: 100,
+		},
+	}
+
+	assert.Equal(t, expected, OutlierRanking(view, nil))
+	assert.Empty(t, OutlierRanking(view, &OutlierCriteria{ZScore: 20, MinLines: 50}))
+	assert.Equal(t, expected, OutlierRanking(view, &OutlierCriteria{Percentile: 100, MinLines: 50}))
+
+	// Without a minimum size the tiny SubView is also an outlier, compared with all other SubViews.
+	outliers := OutlierRanking(view, &OutlierCriteria{ZScore: 2})
+	assert.Len(t, outliers, 4)
+	assert.Equal(t, expected, outliers[:2])
+	assert.Equal(t, "tiny", outliers[2].Path)
+	assert.Equal(t, 7, outliers[2].Peers)
+}
+
+func Test_OutlierRankingFivePeers(t *testing.T) {
+	newView := func(issueCounts ...int) *report.View {
+		view := &report.View{Linters: []string{"golint"}, SubViews: map[string]*report.SubView{}}
+		for idx, issueCount := range issueCounts {
+			subView := &report.SubView{Path: string(rune('a' + idx)), LineCount: 100, Issues: map[string][]*result.Issue{}}
+			for i := 0; i < issueCount; i++ {
+				subView.Issues["golint"] = append(subView.Issues["golint"], &result.Issue{FromLinter: "golint"})
+			}
+
+			view.SubViews[subView.Path] = subView
+		}
+
+		return view
+	}
+
+	outliers := OutlierRanking(newView(2, 3, 2, 3, 8), nil)
+	assert.Len(t, outliers, 2)
+	assert.Equal(t, &Outlier{
+		Path:       "e",
+		Linter:     AllLinters,
+		Lines:      100,
+		Issues:     8,
+		Rate:       80,
+		Peers:      4,
+		PeerMean:   25,
+		PeerStdDev: 5,
+		ZScore:     11,
+		Percentile: 100,
+	}, outliers[0])
+
+	// When all other peers share the same rate any higher rate is an outlier.
+	outliers = OutlierRanking(newView(2, 2, 2, 2, 3), nil)
+	assert.Len(t, outliers, 2)
+	assert.Equal(t, "e", outliers[0].Path)
+	assert.True(t, math.IsInf(outliers[0].ZScore, 1))
+
+	assert.Empty(t, OutlierRanking(newView(2, 2, 2, 2, 2), nil))
+}
+
+func Test_OutlierCriteria(t *testing.T) {
+	assert.Equal(t, "z-score above 2.0 among peers of comparable size with at least 50 lines", DefaultOutlierCriteria().String())
+	assert.Equal(
+		t,
+		"z-score above 1.5 or percentile of at least 95 among peers of comparable size with at least 0 lines",
+		(&OutlierCriteria{ZScore: 1.5, Percentile: 95}).String(),
+	)
+}
@@ -0,0 +1,277 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/Helcaraxan/goality/lib/report"
+)
+
+// ScoreModel determines how the issues of a SubView are condensed into a single score ranging from
+// 0 to 100 and a corresponding letter grade. The score is derived as follows:
+//
+//  1. For each linter the number of issues, each counted with the multiplier of its severity, is
+//     multiplied by the linter's weight and divided by the number of lines, in thousands, as
+//     determined by the View's rate metric.
+//  2. Each linter's weighted density is capped at the linter's cap, if it has one, so that a single
+//     noisy linter can not dominate the score.
+//  3. The capped densities are summed up into a total density D.
+//  4. The score is 100 * 2^(-D / HalfDensity): it is 100 without issues and halves every time the
+//     total density increases by HalfDensity.
+//  5. The grade is the one with the highest minimum score that is not above the score, or F if the
+//     score is below all of them.
+type ScoreModel struct {
+	// Weights holds the weight of each linter's issues. Linters that are not listed have the default
+	// weight.
+	Weights       map[string]float64 `yaml:"weights" json:"weights,omitempty"`
+	DefaultWeight float64            `yaml:"default-weight" json:"default_weight"`
+	// Caps holds the maximum weighted issue density of each linter. Linters that are not listed have
+	// the default cap. A cap of zero means that the density is not capped.
+	Caps       map[string]float64 `yaml:"caps" json:"caps,omitempty"`
+	DefaultCap float64            `yaml:"default-cap" json:"default_cap"`
+	// Severities holds the multiplier of the issues of each severity. Severities that are not listed
+	// have a multiplier of 1.
+	Severities map[string]float64 `yaml:"severities" json:"severities,omitempty"`
+	// HalfDensity is the total weighted issue density, per 1k lines, at which the score is 50.
+	HalfDensity float64 `yaml:"half-density" json:"half_density"`
+	// Grades holds the minimum score for each grade.
+	Grades map[string]float64 `yaml:"grades" json:"grades"`
+}
+
+// FailingGrade is the grade of scores that are below the minimum of all grades of a ScoreModel.
+const FailingGrade = "F"
+
+// DefaultScoreModel returns the ScoreModel in which all linters have the same weight and no caps.
+func DefaultScoreModel() *ScoreModel {
+	return &ScoreModel{
+		DefaultWeight: 1,
+		HalfDensity:   10,
+		Grades: map[string]float64{
+			"A": 90,
+			"B": 75,
+			"C": 60,
+			"D": 40,
+		},
+	}
+}
+
+// LoadScoreModel reads a ScoreModel from the given YAML file. Settings that are not present in the
+// file keep the value of the DefaultScoreModel.
+func LoadScoreModel(path string) (*ScoreModel, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	model := DefaultScoreModel()
+
+	// Grades are replaced as a whole instead of being merged with the default ones.
+	var grades struct {
+		Grades map[string]float64 `yaml:"grades"`
+	}
+
+	if err = yaml.Unmarshal(content, &grades); err != nil {
+		return nil, fmt.Errorf("could not parse score model from %q: %v", path, err)
+	}
+
+	if grades.Grades != nil {
+		model.Grades = nil
+	}
+
+	if err = yaml.UnmarshalStrict(content, model); err != nil {
+		return nil, fmt.Errorf("could not parse score model from %q: %v", path, err)
+	}
+
+	if err = model.validate(); err != nil {
+		return nil, fmt.Errorf("invalid score model in %q: %v", path, err)
+	}
+
+	return model, nil
+}
+
+func (m *ScoreModel) validate() error {
+	if m.HalfDensity <= 0 {
+		return errors.New("the half-density must be strictly positive")
+	}
+
+	if m.DefaultWeight < 0 || m.DefaultCap < 0 {
+		return errors.New("the default weight and cap must not be negative")
+	}
+
+	for linter, weight := range m.Weights {
+		if weight < 0 {
+			return fmt.Errorf("the weight of %q must not be negative", linter)
+		}
+	}
+
+	for linter, limit := range m.Caps {
+		if limit < 0 {
+			return fmt.Errorf("the cap of %q must not be negative", linter)
+		}
+	}
+
+	for severity, multiplier := range m.Severities {
+		if _, ok := report.ParseSeverity(severity); !ok {
+			return fmt.Errorf("unknown severity %q", severity)
+		} else if multiplier < 0 {
+			return fmt.Errorf("the multiplier of severity %q must not be negative", severity)
+		}
+	}
+
+	for grade, minimum := range m.Grades {
+		if grade == FailingGrade {
+			return fmt.Errorf("grade %q is reserved for scores below all other grades", FailingGrade)
+		} else if minimum < 0 || minimum > 100 {
+			return fmt.Errorf("the minimum score of grade %q must be between 0 and 100", grade)
+		}
+	}
+
+	return nil
+}
+
+func (m *ScoreModel) weight(linter string) float64 {
+	if weight, ok := m.Weights[linter]; ok {
+		return weight
+	}
+
+	return m.DefaultWeight
+}
+
+func (m *ScoreModel) cap(linter string) float64 {
+	if limit, ok := m.Caps[linter]; ok {
+		return limit
+	}
+
+	return m.DefaultCap
+}
+
+func (m *ScoreModel) multiplier(severity report.Severity) float64 {
+	if multiplier, ok := m.Severities[severity.String()]; ok {
+		return multiplier
+	}
+
+	return 1
+}
+
+// Score is the outcome of applying a ScoreModel to a SubView.
+type Score struct {
+	Value float64
+	Grade string
+	// Density is the total weighted and capped issue density from which the score was derived.
+	Density float64
+	// Contributions holds the part of the Density that originates from each linter with issues.
+	Contributions map[string]float64
+}
+
+// Score computes the score of the given SubView, which belongs to the given View.
+func (m *ScoreModel) Score(view *report.View, subView *report.SubView) *Score {
+	score := &Score{}
+
+	// Issues in a SubView without any lines are treated as if they were located in a single line.
+	lines := subView.Lines(view.RateMetric)
+	if lines == 0 {
+		lines = 1
+	}
+
+	for _, linter := range view.Linters {
+		weight := m.weight(linter)
+		if len(subView.Issues[linter]) == 0 || weight == 0 {
+			continue
+		}
+
+		var issueCount float64
+		for _, issue := range subView.Issues[linter] {
+			issueCount += m.multiplier(subView.Severity(issue))
+		}
+
+		density := 1000 * weight * issueCount / float64(lines)
+
+		if limit := m.cap(linter); limit > 0 && density > limit {
+			density = limit
+		}
+
+		if score.Contributions == nil {
+			score.Contributions = map[string]float64{}
+		}
+
+		score.Contributions[linter] = density
+		score.Density += density
+	}
+
+	score.Value = 100 * math.Pow(2, -score.Density/m.HalfDensity)
+	score.Grade = m.grade(score.Value)
+
+	return score
+}
+
+func (m *ScoreModel) grade(value float64) string {
+	grade, best := FailingGrade, -1.0
+
+	for name, minimum := range m.Grades {
+		if value >= minimum && (minimum > best || (minimum == best && name < grade)) {
+			grade, best = name, minimum
+		}
+	}
+
+	return grade
+}
+
+// Description documents how scores are derived with this model, for the given rate metric.
+func (m *ScoreModel) Description(metric report.LineMetric) string {
+	var grades []string
+	for grade := range m.Grades {
+		grades = append(grades, grade)
+	}
+
+	sort.Slice(grades, func(i int, j int) bool {
+		if m.Grades[grades[i]] != m.Grades[grades[j]] {
+			return m.Grades[grades[i]] > m.Grades[grades[j]]
+		}
+
+		return grades[i] < grades[j]
+	})
+
+	for idx, grade := range grades {
+		grades[idx] = fmt.Sprintf("%s >= %.0f", grade, m.Grades[grade])
+	}
+
+	description := []string{
+		fmt.Sprintf(
+			"Score: 100 x 2^(-D / %.2f) where D is the sum over all linters of min(cap, weight x issues per 1K %s)",
+			m.HalfDensity,
+			metric,
+		),
+		"Weights: " + describeSettings(m.Weights, m.DefaultWeight),
+		"Caps (0 means uncapped): " + describeSettings(m.Caps, m.DefaultCap),
+	}
+
+	if len(m.Severities) > 0 {
+		description = append(description, "Severity multipliers: "+describeSettings(m.Severities, 1))
+	}
+
+	description = append(description, "Grades: "+strings.Join(append(grades, FailingGrade+" otherwise"), ", "))
+
+	return strings.Join(description, "\n")
+}
+
+func describeSettings(settings map[string]float64, defaultValue float64) string {
+	var linters []string
+	for linter := range settings {
+		linters = append(linters, linter)
+	}
+
+	sort.Strings(linters)
+
+	description := []string{fmt.Sprintf("default %.2f", defaultValue)}
+	for _, linter := range linters {
+		description = append(description, fmt.Sprintf("%s %.2f", linter, settings[linter]))
+	}
+
+	return strings.Join(description, ", ")
+}
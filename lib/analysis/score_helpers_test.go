@@ -0,0 +1,176 @@
+package analysis
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golangci/golangci-lint/pkg/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/goality/lib/report"
+)
+
+func Test_Score(t *testing.T) {
+	view := &report.View{Linters: []string{"errcheck", "lll", "misspell"}}
+
+	subView := func(lines int, errcheck int, lll int, misspell int) *report.SubView {
+		issues := func(count int) []*result.Issue {
+			var issues []*result.Issue
+			for i := 0; i < count; i++ {
+				issues = append(issues, &result.Issue{})
+			}
+			return issues
+		}
+
+		return &report.SubView{
+			LineCount: lines,
+			Issues: map[string][]*result.Issue{
+				"errcheck": issues(errcheck),
+				"lll":      issues(lll),
+				"misspell": issues(misspell),
+			},
+		}
+	}
+
+	model := DefaultScoreModel()
+	model.Weights = map[string]float64{"errcheck": 2, "misspell": 0}
+	model.Caps = map[string]float64{"lll": 5}
+
+	testcases := map[string]struct {
+		subView       *report.SubView
+		expectedScore *Score
+	}{
+		"NoIssues": {
+			subView:       subView(1000, 0, 0, 0),
+			expectedScore: &Score{Value: 100, Grade: "A"},
+		},
+		"Weighted": {
+			subView: subView(1000, 5, 0, 0),
+			expectedScore: &Score{
+				Value:         50,
+				Grade:         "D",
+				Density:       10,
+				Contributions: map[string]float64{"errcheck": 10},
+			},
+		},
+		"Capped": {
+			subView: subView(500, 0, 20, 0),
+			expectedScore: &Score{
+				Value:         70.71067811865476,
+				Grade:         "C",
+				Density:       5,
+				Contributions: map[string]float64{"lll": 5},
+			},
+		},
+		"Ignored": {
+			subView:       subView(1000, 0, 0, 30),
+			expectedScore: &Score{Value: 100, Grade: "A"},
+		},
+		"Failing": {
+			subView: subView(200, 2, 1, 0),
+			expectedScore: &Score{
+				Value:         17.67766952966369,
+				Grade:         FailingGrade,
+				Density:       25,
+				Contributions: map[string]float64{"errcheck": 20, "lll": 5},
+			},
+		},
+		"NoLines": {
+			subView: subView(0, 0, 1, 0),
+			expectedScore: &Score{
+				Value:         70.71067811865476,
+				Grade:         "C",
+				Density:       5,
+				Contributions: map[string]float64{"lll": 5},
+			},
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			score := model.Score(view, testcase.subView)
+			assert.InDelta(t, testcase.expectedScore.Value, score.Value, 1e-9)
+			assert.Equal(t, testcase.expectedScore.Grade, score.Grade)
+			assert.Equal(t, testcase.expectedScore.Density, score.Density)
+			assert.Equal(t, testcase.expectedScore.Contributions, score.Contributions)
+		})
+	}
+}
+
+func Test_ScoreSeverities(t *testing.T) {
+	errorIssue, warningIssue, infoIssue := &result.Issue{}, &result.Issue{}, &result.Issue{}
+
+	view := &report.View{Linters: []string{"errcheck"}}
+	subView := &report.SubView{
+		LineCount: 1000,
+		Issues:    map[string][]*result.Issue{"errcheck": {errorIssue, warningIssue, infoIssue}},
+		Severities: map[*result.Issue]report.Severity{
+			errorIssue: report.SeverityError,
+			infoIssue:  report.SeverityInfo,
+		},
+	}
+
+	model := DefaultScoreModel()
+	model.Severities = map[string]float64{"error": 5, "info": 0}
+
+	score := model.Score(view, subView)
+	assert.InDelta(t, 65.97539553864472, score.Value, 1e-9)
+	assert.Equal(t, map[string]float64{"errcheck": 6}, score.Contributions)
+}
+
+func Test_LoadScoreModel(t *testing.T) {
+	modelFile := writeTestModel(t, `weights:
+  errcheck: 3
+default-cap: 50
+grades:
+  Excellent: 95
+  Good: 70
+`)
+	defer os.Remove(modelFile)
+
+	model, err := LoadScoreModel(modelFile)
+	require.NoError(t, err)
+	assert.Equal(t, &ScoreModel{
+		Weights:       map[string]float64{"errcheck": 3},
+		DefaultWeight: 1,
+		DefaultCap:    50,
+		HalfDensity:   10,
+		Grades:        map[string]float64{"Excellent": 95, "Good": 70},
+	}, model)
+
+	expectedDescription := `Score: 100 x 2^(-D / 10.00) where D is the sum over all linters of min(cap, weight x issues per 1K LoC)
+Weights: default 1.00, errcheck 3.00
+Caps (0 means uncapped): default 50.00
+Grades: Excellent >= 95, Good >= 70, F otherwise`
+	assert.Equal(t, expectedDescription, model.Description(report.LineMetricCode))
+
+	for name, content := range map[string]string{
+		"UnknownField":     "weight:\n  errcheck: 3\n",
+		"NegativeWeight":   "weights:\n  errcheck: -1\n",
+		"NoHalfDensity":    "half-density: 0\n",
+		"ReservedGrade":    "grades:\n  F: 10\n",
+		"OutOfRangeGrade":  "grades:\n  A: 110\n",
+		"NegativeDefaults": "default-cap: -5\n",
+		"UnknownSeverity":  "severities:\n  fatal: 2\n",
+		"NegativeSeverity": "severities:\n  error: -2\n",
+	} {
+		invalidFile := writeTestModel(t, content)
+		_, err = LoadScoreModel(invalidFile)
+		assert.Error(t, err, "Should not accept a score model with %s.", name)
+		_ = os.Remove(invalidFile)
+	}
+}
+
+func writeTestModel(t *testing.T, content string) string {
+	modelFile, err := ioutil.TempFile("", "goality-score")
+	require.NoError(t, err)
+
+	_, err = modelFile.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, modelFile.Close())
+
+	return modelFile.Name()
+}
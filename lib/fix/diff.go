@@ -0,0 +1,108 @@
+package fix
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change of a unified diff.
+const diffContext = 3
+
+// hunk is a group of edits whose context lines overlap. Its range covers the edits and their context.
+type hunk struct {
+	from  int
+	to    int
+	edits []*edit
+}
+
+// unifiedDiff describes the given edits of the file at the given path in the unified diff format.
+func unifiedDiff(path string, lines []string, edits []*edit) string {
+	if len(edits) == 0 {
+		return ""
+	}
+
+	var hunks []*hunk
+	for _, e := range edits {
+		from, to := max(1, e.from-diffContext), min(len(lines), e.to+diffContext)
+
+		if last := len(hunks) - 1; last >= 0 && from <= hunks[last].to+1 {
+			hunks[last].to = to
+			hunks[last].edits = append(hunks[last].edits, e)
+			continue
+		}
+
+		hunks = append(hunks, &hunk{from: from, to: to, edits: []*edit{e}})
+	}
+
+	diff := &strings.Builder{}
+	fmt.Fprintf(diff, "--- a/%s\n+++ b/%s\n", path, path)
+
+	// offset is the difference between the line numbers of the fixed and of the original file.
+	var offset int
+
+	for _, h := range hunks {
+		var body []string
+
+		oldCount, newCount, position := 0, 0, h.from
+		for _, e := range h.edits {
+			for ; position < e.from; position++ {
+				body = append(body, " "+lines[position-1])
+			}
+
+			for ; position <= e.to; position++ {
+				body = append(body, "-"+lines[position-1])
+			}
+
+			for _, line := range e.newLines {
+				body = append(body, "+"+line)
+			}
+
+			oldCount += e.to - e.from + 1
+			newCount += len(e.newLines)
+		}
+
+		for ; position <= h.to; position++ {
+			body = append(body, " "+lines[position-1])
+		}
+
+		context := h.to - h.from + 1 - oldCount
+		oldCount += context
+		newCount += context
+
+		fmt.Fprintf(diff, "@@ -%s +%s @@\n", hunkRange(h.from, oldCount), hunkRange(h.from+offset, newCount))
+		diff.WriteString(strings.Join(body, "\n") + "\n")
+
+		offset += newCount - oldCount
+	}
+
+	return diff.String()
+}
+
+// hunkRange formats the start and length of a hunk. Empty ranges start at the line preceding them.
+func hunkRange(start int, count int) string {
+	if count == 0 {
+		start--
+	}
+
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
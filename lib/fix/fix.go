@@ -0,0 +1,416 @@
+// Package fix applies the replacements that linters suggest for their issues to the files of an
+// analysed project.
+package fix
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golangci/golangci-lint/pkg/result"
+
+	"github.com/Helcaraxan/goality/lib/analysis"
+	"github.com/Helcaraxan/goality/lib/report"
+)
+
+// FixOpts selects the issues whose replacements are applied. Without any options the replacements
+// of all issues are applied.
+type FixOpts struct {
+	linters    []string
+	paths      []string
+	categories []*analysis.IssueCategory
+}
+
+// WithLinters only applies the replacements of the issues reported by the given linters.
+func WithLinters(linters ...string) *FixOpts {
+	return &FixOpts{linters: linters}
+}
+
+// WithPaths only applies replacements to the files located at, or under, the given relative paths.
+func WithPaths(paths ...string) *FixOpts {
+	return &FixOpts{paths: paths}
+}
+
+// WithCategories only applies the replacements of the issues of the given categories.
+func WithCategories(categories ...*analysis.IssueCategory) *FixOpts {
+	return &FixOpts{categories: categories}
+}
+
+// fixFilter is the aggregate of a set of FixOpts.
+type fixFilter struct {
+	linters map[string]struct{}
+	paths   []string
+	issues  map[*result.Issue]struct{}
+}
+
+func aggregateFixOpts(opts ...*FixOpts) *fixFilter {
+	filter := &fixFilter{}
+
+	for _, opt := range opts {
+		for _, linter := range opt.linters {
+			if filter.linters == nil {
+				filter.linters = map[string]struct{}{}
+			}
+
+			filter.linters[linter] = struct{}{}
+		}
+
+		for _, path := range opt.paths {
+			filter.paths = append(filter.paths, filepath.Clean(path))
+		}
+
+		for _, category := range opt.categories {
+			for _, issue := range category.Issues {
+				if filter.issues == nil {
+					filter.issues = map[*result.Issue]struct{}{}
+				}
+
+				filter.issues[issue] = struct{}{}
+			}
+		}
+	}
+
+	return filter
+}
+
+func (f *fixFilter) matchPath(path string) bool {
+	if len(f.paths) == 0 {
+		return true
+	}
+
+	for _, filterPath := range f.paths {
+		if filterPath == "." || path == filterPath || strings.HasPrefix(path, filterPath+string(os.PathSeparator)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *fixFilter) matchIssue(issue *result.Issue) bool {
+	if issue.Replacement == nil {
+		return false
+	}
+
+	if f.linters != nil {
+		if _, ok := f.linters[issue.FromLinter]; !ok {
+			return false
+		}
+	}
+
+	if f.issues != nil {
+		if _, ok := f.issues[issue]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FileFix holds the replacements that are applied to a single file of a project.
+type FileFix struct {
+	// Path is the relative path of the file in the project.
+	Path string
+	// Fixed lists the issues whose replacements are applied. Skipped lists those whose replacements
+	// are invalid or overlap with those of other issues.
+	Fixed   []*result.Issue
+	Skipped []*result.Issue
+
+	absPath string
+	hash    string
+	lines   []string
+	// noFinalNewline is set when the last line of the file is not terminated by a newline.
+	noFinalNewline bool
+	edits          []*edit
+}
+
+// Plan determines the replacements that apply to each of the files of the given project. Generated
+// files are left untouched. It fails if any of the concerned files changed since it was analysed.
+func Plan(project *report.Project, opts ...*FixOpts) ([]*FileFix, error) {
+	filter := aggregateFixOpts(opts...)
+
+	var (
+		fixes   []*FileFix
+		changed []string
+	)
+
+	for _, file := range project.Files() {
+		if file.IsGenerated || !filter.matchPath(file.Path) {
+			continue
+		}
+
+		var issues []*result.Issue
+		for _, linterIssues := range file.Issues {
+			for _, issue := range linterIssues {
+				if filter.matchIssue(issue) {
+					issues = append(issues, issue)
+				}
+			}
+		}
+
+		if len(issues) == 0 {
+			continue
+		}
+
+		fileFix := &FileFix{
+			Path:    file.Path,
+			absPath: filepath.Join(project.Path, file.Path),
+			hash:    file.Hash,
+		}
+
+		content, err := fileFix.read()
+		if err == errFileChanged {
+			changed = append(changed, file.Path)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		fileFix.lines = splitLines(content)
+		fileFix.noFinalNewline = len(content) > 0 && content[len(content)-1] != '\n'
+		fileFix.edits, fileFix.Fixed, fileFix.Skipped = planEdits(fileFix.lines, issues)
+
+		if len(fileFix.edits) > 0 {
+			fixes = append(fixes, fileFix)
+		}
+	}
+
+	if len(changed) > 0 {
+		return nil, fmt.Errorf("refusing to fix files that changed since they were analysed: %s", strings.Join(changed, ", "))
+	}
+
+	return fixes, nil
+}
+
+// Apply writes the fixed content of each of the given files. It fails, without writing anything, if
+// any of the files changed since it was analysed.
+func Apply(fixes []*FileFix) error {
+	for _, fileFix := range fixes {
+		if _, err := fileFix.read(); err == errFileChanged {
+			return fmt.Errorf("refusing to fix %q as it changed since it was analysed", fileFix.Path)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	for _, fileFix := range fixes {
+		if err := fileFix.write(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Diff returns the changes made to the file in the unified diff format.
+func (f *FileFix) Diff() string {
+	return unifiedDiff(f.Path, f.lines, f.edits)
+}
+
+var errFileChanged = errors.New("file changed since it was analysed")
+
+func (f *FileFix) read() ([]byte, error) {
+	content, err := ioutil.ReadFile(f.absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if fmt.Sprintf("%x", sha256.Sum256(content)) != f.hash {
+		return nil, errFileChanged
+	}
+
+	return content, nil
+}
+
+// write replaces the file via a temporary file in the same directory so that it is never left in a
+// partially written state.
+func (f *FileFix) write() error {
+	info, err := os.Stat(f.absPath)
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(f.absPath), "."+filepath.Base(f.absPath)+".goality-fix")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(tmpFile.Name()) }()
+
+	fixed := joinLines(applyEdits(f.lines, f.edits))
+	if f.noFinalNewline {
+		fixed = strings.TrimSuffix(fixed, "\n")
+	}
+
+	if _, err = tmpFile.WriteString(fixed); err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("could not write the fixed content of %q: %v", f.Path, err)
+	}
+
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(tmpFile.Name(), info.Mode()); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), f.absPath)
+}
+
+// edit replaces the lines from 'from' to 'to', both one-based and inclusive, with new lines.
+type edit struct {
+	from     int
+	to       int
+	newLines []string
+}
+
+// planEdits turns the replacements of the given issues into non-overlapping edits. Inline fixes on
+// the same line are merged. Replacements that are out of range for the file, or that overlap with a
+// previous one, are skipped.
+func planEdits(lines []string, issues []*result.Issue) (edits []*edit, fixed []*result.Issue, skipped []*result.Issue) {
+	sort.SliceStable(issues, func(i int, j int) bool {
+		if issues[i].Line() != issues[j].Line() {
+			return issues[i].Line() < issues[j].Line()
+		}
+
+		if issues[i].Column() != issues[j].Column() {
+			return issues[i].Column() < issues[j].Column()
+		}
+
+		return issues[i].FromLinter < issues[j].FromLinter
+	})
+
+	var lastLine int
+
+	for start := 0; start < len(issues); {
+		end := start + 1
+		for end < len(issues) && issues[end].Line() == issues[start].Line() {
+			end++
+		}
+
+		lineIssues := issues[start:end]
+		start = end
+
+		lineEdit, lineFixed := lineEdit(lines, lineIssues)
+		if lineEdit == nil || lineEdit.from <= lastLine {
+			skipped = append(skipped, lineIssues...)
+			continue
+		}
+
+		edits = append(edits, lineEdit)
+		fixed = append(fixed, lineFixed...)
+
+		for _, issue := range lineIssues {
+			if !containsIssue(lineFixed, issue) {
+				skipped = append(skipped, issue)
+			}
+		}
+
+		lastLine = lineEdit.to
+	}
+
+	return edits, fixed, skipped
+}
+
+// lineEdit determines the edit for the issues located on a single line. Multiple issues are only
+// combined if all of them are inline fixes that do not overlap, otherwise only the first one is used.
+func lineEdit(lines []string, issues []*result.Issue) (*edit, []*result.Issue) {
+	lineRange := issues[0].GetLineRange()
+	if lineRange.From < 1 || lineRange.To < lineRange.From || lineRange.To > len(lines) {
+		return nil, nil
+	}
+
+	for _, issue := range issues {
+		if !isInline(issue) {
+			issues = issues[:1]
+			break
+		}
+	}
+
+	if !isInline(issues[0]) {
+		if issues[0].Replacement.NeedOnlyDelete {
+			return &edit{from: lineRange.From, to: lineRange.To}, issues
+		}
+
+		return &edit{from: lineRange.From, to: lineRange.To, newLines: issues[0].Replacement.NewLines}, issues
+	}
+
+	lineNumber := issues[0].Line()
+	line := lines[lineNumber-1]
+
+	sort.SliceStable(issues, func(i int, j int) bool {
+		return issues[i].Replacement.Inline.StartCol < issues[j].Replacement.Inline.StartCol
+	})
+
+	var (
+		fixedLine strings.Builder
+		position  int
+	)
+
+	for _, issue := range issues {
+		inline := issue.Replacement.Inline
+		if inline.StartCol < position || inline.Length < 0 || inline.StartCol+inline.Length > len(line) {
+			return nil, nil
+		}
+
+		fixedLine.WriteString(line[position:inline.StartCol])
+		fixedLine.WriteString(inline.NewString)
+		position = inline.StartCol + inline.Length
+	}
+
+	fixedLine.WriteString(line[position:])
+
+	return &edit{from: lineNumber, to: lineNumber, newLines: []string{fixedLine.String()}}, issues
+}
+
+func isInline(issue *result.Issue) bool {
+	replacement := issue.Replacement
+	return replacement.Inline != nil && !replacement.NeedOnlyDelete && len(replacement.NewLines) == 0
+}
+
+func containsIssue(issues []*result.Issue, issue *result.Issue) bool {
+	for _, candidate := range issues {
+		if candidate == issue {
+			return true
+		}
+	}
+
+	return false
+}
+
+func applyEdits(lines []string, edits []*edit) []string {
+	var fixed []string
+
+	position := 1
+	for _, e := range edits {
+		fixed = append(fixed, lines[position-1:e.from-1]...)
+		fixed = append(fixed, e.newLines...)
+		position = e.to + 1
+	}
+
+	return append(fixed, lines[position-1:]...)
+}
+
+// splitLines returns the lines of the given content. A trailing newline does not start a new line.
+func splitLines(content []byte) []string {
+	text := string(content)
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+func joinLines(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,200 @@
+package fix
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golangci/golangci-lint/pkg/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/goality/lib/analysis"
+)
+
+var testLines = []string{
+	"package main",
+	"",
+	"import (",
+	"\t\"fmt\"",
+	")",
+	"",
+	"// recieve the mesage",
+	"func main() {",
+	"\tfmt.Println(\"hello\")",
+	"}",
+}
+
+func inlineIssue(linter string, line int, col int, length int, replacement string) *result.Issue {
+	return &result.Issue{
+		FromLinter:  linter,
+		Pos:         tokenPosition(line, col+1),
+		Replacement: &result.Replacement{Inline: &result.InlineFix{StartCol: col, Length: length, NewString: replacement}},
+	}
+}
+
+func linesIssue(linter string, from int, to int, newLines ...string) *result.Issue {
+	return &result.Issue{
+		FromLinter:  linter,
+		Pos:         tokenPosition(from, 1),
+		LineRange:   &result.Range{From: from, To: to},
+		Replacement: &result.Replacement{NewLines: newLines, NeedOnlyDelete: len(newLines) == 0},
+	}
+}
+
+func Test_PlanEdits(t *testing.T) {
+	var (
+		receive  = inlineIssue("misspell", 7, 3, 7, "receive")
+		message  = inlineIssue("misspell", 7, 15, 6, "message")
+		overlap  = inlineIssue("misspell", 7, 5, 3, "foo")
+		imports  = linesIssue("gofmt", 3, 5, "import \"fmt\"")
+		inImport = linesIssue("goimports", 4, 4, "\t\"os\"")
+		deletion = linesIssue("unused", 9, 9)
+		invalid  = linesIssue("unused", 9, 12)
+	)
+
+	testcases := map[string]struct {
+		issues          []*result.Issue
+		expectedEdits   []*edit
+		expectedFixed   []*result.Issue
+		expectedSkipped []*result.Issue
+	}{
+		"InlineFixes": {
+			issues:        []*result.Issue{message, receive},
+			expectedEdits: []*edit{{from: 7, to: 7, newLines: []string{"// receive the message"}}},
+			expectedFixed: []*result.Issue{receive, message},
+		},
+		"OverlappingInlineFixes": {
+			issues:          []*result.Issue{receive, overlap},
+			expectedSkipped: []*result.Issue{receive, overlap},
+		},
+		"OverlappingRanges": {
+			issues:          []*result.Issue{inImport, imports, deletion},
+			expectedEdits:   []*edit{{from: 3, to: 5, newLines: []string{"import \"fmt\""}}, {from: 9, to: 9}},
+			expectedFixed:   []*result.Issue{imports, deletion},
+			expectedSkipped: []*result.Issue{inImport},
+		},
+		"InvalidRange": {
+			issues:          []*result.Issue{invalid},
+			expectedSkipped: []*result.Issue{invalid},
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			edits, fixed, skipped := planEdits(testLines, testcase.issues)
+			assert.Equal(t, testcase.expectedEdits, edits)
+			assert.Equal(t, testcase.expectedFixed, fixed)
+			assert.Equal(t, testcase.expectedSkipped, skipped)
+		})
+	}
+}
+
+func Test_UnifiedDiff(t *testing.T) {
+	edits := []*edit{
+		{from: 3, to: 5, newLines: []string{"import \"fmt\""}},
+		{from: 7, to: 7, newLines: []string{"// receive the message"}},
+	}
+
+	expectedDiff := `--- a/main.go
++++ b/main.go
+@@ -1,10 +1,8 @@
+ package main
+ 
+-import (
+-	"fmt"
+-)
++import "fmt"
+ 
+-// recieve the mesage
++// receive the message
+ func main() {
+ 	fmt.Println("hello")
+ }
+`
+	assert.Equal(t, expectedDiff, unifiedDiff("main.go", testLines, edits))
+
+	lines := append(append([]string{}, testLines...), testLines...)
+	edits = []*edit{{from: 2, to: 2}, {from: 19, to: 19, newLines: []string{"\tfmt.Println(\"bye\")"}}}
+
+	expectedDiff = `--- a/main.go
++++ b/main.go
+@@ -1,5 +1,4 @@
+ package main
+-
+ import (
+ 	"fmt"
+ )
+@@ -16,5 +15,5 @@
+ 
+ // recieve the mesage
+ func main() {
+-	fmt.Println("hello")
++	fmt.Println("bye")
+ }
+`
+	assert.Equal(t, expectedDiff, unifiedDiff("main.go", lines, edits))
+}
+
+func Test_Apply(t *testing.T) {
+	projectPath, err := ioutil.TempDir("", "goality-fix")
+	require.NoError(t, err)
+
+	defer func() { _ = os.RemoveAll(projectPath) }()
+
+	content := []byte("package main\n\n// recieve\nfunc main() {}")
+	filePath := filepath.Join(projectPath, "main.go")
+	require.NoError(t, ioutil.WriteFile(filePath, content, 0600))
+
+	fileFix := &FileFix{
+		Path:           "main.go",
+		absPath:        filePath,
+		hash:           fmt.Sprintf("%x", sha256.Sum256(content)),
+		lines:          splitLines(content),
+		noFinalNewline: true,
+		edits:          []*edit{{from: 3, to: 3, newLines: []string{"// receive"}}},
+	}
+
+	require.NoError(t, Apply([]*FileFix{fileFix}))
+
+	fixed, err := ioutil.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n\n// receive\nfunc main() {}", string(fixed))
+
+	info, err := os.Stat(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode())
+
+	// The file now differs from the analysed content.
+	assert.Error(t, Apply([]*FileFix{fileFix}))
+}
+
+func Test_AggregateFixOpts(t *testing.T) {
+	issue := &result.Issue{FromLinter: "misspell", Replacement: &result.Replacement{}}
+	other := &result.Issue{FromLinter: "misspell", Replacement: &result.Replacement{}}
+
+	filter := aggregateFixOpts(
+		WithLinters("misspell"),
+		WithPaths("cmd", "internal/app/"),
+		WithCategories(&analysis.IssueCategory{Issues: []*result.Issue{issue}}),
+	)
+
+	assert.True(t, filter.matchIssue(issue))
+	assert.False(t, filter.matchIssue(other))
+	assert.False(t, filter.matchIssue(&result.Issue{FromLinter: "misspell"}))
+	assert.False(t, aggregateFixOpts(WithLinters("gofmt")).matchIssue(issue))
+
+	assert.True(t, filter.matchPath(filepath.Join("cmd", "main.go")))
+	assert.True(t, filter.matchPath(filepath.Join("internal", "app", "app.go")))
+	assert.False(t, filter.matchPath(filepath.Join("cmdline", "main.go")))
+	assert.True(t, aggregateFixOpts().matchPath("main.go"))
+}
+
+func tokenPosition(line int, column int) token.Position {
+	return token.Position{Filename: "main.go", Line: line, Column: column}
+}
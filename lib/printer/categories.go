@@ -1,9 +1,12 @@
 package printer
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 
 	"github.com/Helcaraxan/goality/lib/analysis"
 	"github.com/Helcaraxan/goality/lib/printer/formatters"
@@ -11,34 +14,263 @@ import (
 
 var maxIssueTextWidth = 100
 
-func PrintCategories(w io.Writer, categories analysis.IssueCategories, format FormatType) error {
+type jsonCategory struct {
+	Key         string `json:"key"`
+	Linter      string `json:"linter"`
+	Template    string `json:"template"`
+	Occurrences int    `json:"occurrences"`
+	// Effort is expressed in minutes.
+	Effort      *float64                `json:"effort,omitempty"`
+	Directories []*jsonCategoryLocation `json:"directories,omitempty"`
+	Paths       map[string]int          `json:"paths,omitempty"`
+}
+
+type jsonCategoryLocation struct {
+	Directory   string                    `json:"directory"`
+	Occurrences []*jsonCategoryOccurrence `json:"occurrences"`
+}
+
+type jsonCategoryOccurrence struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column,omitempty"`
+	Text   string `json:"text"`
+}
+
+func newJSONCategory(category *analysis.IssueCategory) *jsonCategory {
+	return &jsonCategory{
+		Key:         category.Key(),
+		Linter:      category.Linter,
+		Template:    category.Representative,
+		Occurrences: len(category.Issues),
+	}
+}
+
+func PrintCategories(w io.Writer, categories analysis.IssueCategories, format FormatType, opts ...*PrintOpts) error {
+	opt := aggregatePrintOpts(opts...)
+
+	if format == FormatTypeJSON {
+		output := []*jsonCategory{}
+		for _, category := range categories {
+			jsonCategory := newJSONCategory(category)
+			if opt.effort != nil {
+				effort := opt.effort.CategoryEffort(category).Minutes()
+				jsonCategory.Effort = &effort
+			}
+
+			output = append(output, jsonCategory)
+		}
+
+		return encodeJSON(w, output)
+	}
+
 	var (
 		categoryMatrix = [][]string{}
 		headers        = []string{"occurrences", "linter", "issue"}
+		ratios         = []int{1, 1, 1}
 	)
 
+	if opt.effort != nil {
+		headers = append(headers, "effort")
+		ratios = append(ratios, 1)
+	}
+
 	for idx := range categories {
-		issueContent := categories[idx].Representative
-		if len(issueContent) > maxIssueTextWidth {
-			issueContent = issueContent[:maxIssueTextWidth/2+maxIssueTextWidth%2-2] +
-				"...." +
-				issueContent[len(issueContent)-maxIssueTextWidth/2+2:]
-		}
+		issueContent := truncateIssueText(categories[idx].Representative)
 
 		occurrences := fmt.Sprintf("%d", len(categories[idx].Issues))
-		categoryMatrix = append(categoryMatrix, []string{occurrences, categories[idx].Linter, issueContent})
+		line := []string{occurrences, categories[idx].Linter, issueContent}
+		if opt.effort != nil {
+			line = append(line, analysis.FormatEffort(opt.effort.CategoryEffort(categories[idx])))
+		}
+
+		categoryMatrix = append(categoryMatrix, line)
 	}
 
-	var formatter Formatter
+	formatter, err := tableFormatter(format)
+	if err != nil {
+		return err
+	}
+
+	return formatter.PrintTable(w, headers, categoryMatrix, ratios)
+}
 
+// PrintCategoryOccurrences lists the location and full text of each of the issues of the given
+// categories, grouped by directory.
+func PrintCategoryOccurrences(w io.Writer, categories analysis.IssueCategories, format FormatType) error {
 	switch format {
+	case FormatTypeJSON:
+		output := []*jsonCategory{}
+		for _, category := range categories {
+			jsonCategory := newJSONCategory(category)
+			for _, occurrences := range category.Occurrences() {
+				location := &jsonCategoryLocation{Directory: occurrences.Directory}
+				for _, issue := range occurrences.Issues {
+					location.Occurrences = append(location.Occurrences, &jsonCategoryOccurrence{
+						File:   issue.Pos.Filename,
+						Line:   issue.Pos.Line,
+						Column: issue.Pos.Column,
+						Text:   issue.Text,
+					})
+				}
+
+				jsonCategory.Directories = append(jsonCategory.Directories, location)
+			}
+
+			output = append(output, jsonCategory)
+		}
+
+		return encodeJSON(w, output)
+
 	case FormatTypeCSV:
-		formatter = &formatters.CSVFormatter{}
+		// All categories are part of a single table so that the output remains valid CSV.
+		occurrenceMatrix := [][]string{}
+		for _, category := range categories {
+			for _, occurrences := range category.Occurrences() {
+				for _, issue := range occurrences.Issues {
+					occurrenceMatrix = append(occurrenceMatrix, []string{
+						category.Key(),
+						occurrences.Directory,
+						issue.Pos.Filename,
+						strconv.Itoa(issue.Pos.Line),
+						issue.Text,
+					})
+				}
+			}
+		}
+
+		headers := []string{"category", "directory", "file", "line", "issue"}
+
+		return (&formatters.CSVFormatter{}).PrintTable(w, headers, occurrenceMatrix, []int{1, 1, 1, 1, 1})
+
 	case FormatTypeScreen:
-		formatter = &formatters.ScreenFormatter{}
+		for idx, category := range categories {
+			if idx > 0 {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+
+			if _, err := fmt.Fprintf(w, "%s (%d occurrences)\n\n", category.Key(), len(category.Issues)); err != nil {
+				return err
+			}
+
+			// The directory is only printed on the first line of each of its group of occurrences.
+			occurrenceMatrix := [][]string{}
+			for _, occurrences := range category.Occurrences() {
+				for issueIdx, issue := range occurrences.Issues {
+					var directory string
+					if issueIdx == 0 {
+						directory = occurrences.Directory
+					}
+
+					occurrenceMatrix = append(occurrenceMatrix, []string{
+						directory,
+						fmt.Sprintf("%s:%d", issue.Pos.Filename, issue.Pos.Line),
+						issue.Text,
+					})
+				}
+			}
+
+			headers := []string{"directory", "location", "issue"}
+			if err := (&formatters.ScreenFormatter{}).PrintTable(w, headers, occurrenceMatrix, []int{1, 1, 1}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
 	default:
 		return errors.New("unknown format type specified for result printing")
 	}
+}
+
+// PrintCategoryMatrix prints the number of issues of each category in each path of the given matrix,
+// as well as the share of the category's issues that this represents.
+func PrintCategoryMatrix(w io.Writer, matrix *analysis.CategoryMatrix, format FormatType) error {
+	if format == FormatTypeJSON {
+		output := []*jsonCategory{}
+		for idx, category := range matrix.Categories {
+			jsonCategory := newJSONCategory(category)
+			jsonCategory.Paths = map[string]int{}
+			for _, path := range matrix.Paths {
+				if count := matrix.Counts[path][idx]; count > 0 {
+					jsonCategory.Paths[path] = count
+				}
+			}
+
+			output = append(output, jsonCategory)
+		}
+
+		return encodeJSON(w, output)
+	}
+
+	var (
+		headers     = []string{"path"}
+		ratios      = []int{1}
+		valueMatrix = [][]string{}
+	)
+
+	for idx := range matrix.Categories {
+		headers = append(headers, fmt.Sprintf("#%d", idx+1))
+		ratios = append(ratios, 2)
+	}
+
+	for _, path := range matrix.Paths {
+		row := []string{path}
+		for idx, count := range matrix.Counts[path] {
+			share := 100 * float64(count) / float64(len(matrix.Categories[idx].Issues))
+			row = append(row, strconv.Itoa(count), fmt.Sprintf("(%.0f%%)", share))
+		}
+
+		valueMatrix = append(valueMatrix, row)
+	}
+
+	formatter, err := tableFormatter(format)
+	if err != nil {
+		return err
+	}
+
+	if err = formatter.PrintTable(w, headers, valueMatrix, ratios); err != nil {
+		return err
+	}
+
+	if format != FormatTypeScreen {
+		return nil
+	}
+
+	legend := []string{"", "Data-format: occurrences (share of the category's occurrences)"}
+	for idx, category := range matrix.Categories {
+		legend = append(legend, fmt.Sprintf("#%d: %s (%d occurrences)", idx+1, truncateIssueText(category.Key()), len(category.Issues)))
+	}
+
+	_, err = fmt.Fprintln(w, strings.Join(legend, "\n"))
+
+	return err
+}
+
+func truncateIssueText(text string) string {
+	if len(text) <= maxIssueTextWidth {
+		return text
+	}
+
+	return text[:maxIssueTextWidth/2+maxIssueTextWidth%2-2] + "...." + text[len(text)-maxIssueTextWidth/2+2:]
+}
+
+func tableFormatter(format FormatType) (Formatter, error) {
+	switch format {
+	case FormatTypeCSV:
+		return &formatters.CSVFormatter{}, nil
+	case FormatTypeScreen:
+		return &formatters.ScreenFormatter{}, nil
+	default:
+		return nil, errors.New("unknown format type specified for result printing")
+	}
+}
+
+func encodeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
 
-	return formatter.PrintTable(w, headers, categoryMatrix, []int{1, 1, 1})
+	return encoder.Encode(v)
 }
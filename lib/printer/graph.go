@@ -0,0 +1,75 @@
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Helcaraxan/goality/lib/report"
+)
+
+// PrintPackageGraph writes the import graph between the project's packages in Graphviz's DOT format.
+// Packages are coloured from green to red based on their issue rate relative to the highest rate
+// among all packages. Imports that are part of a cycle are drawn in red.
+func PrintPackageGraph(w io.Writer, view *report.View) error {
+	var (
+		maxRate float32
+		rates   = map[string]float32{}
+		labels  = map[string]string{}
+	)
+
+	for _, subView := range view.Packages {
+		var issueCount int
+		for _, linter := range view.Linters {
+			issueCount += len(subView.Issues[linter])
+		}
+
+		rate := subView.OccurrenceRate(issueCount, view.RateMetric)
+		if rate > maxRate {
+			maxRate = rate
+		}
+
+		pkg := subView.Packages[0]
+		rates[pkg.ImportPath] = rate
+		labels[pkg.ImportPath] = fmt.Sprintf("%s\\n%d issues (%.2f)", pkg.Path, issueCount, rate)
+	}
+
+	graph := &strings.Builder{}
+	graph.WriteString("digraph packages {\n")
+	graph.WriteString("  node [shape=box, style=filled];\n")
+
+	for _, subView := range view.Packages {
+		pkg := subView.Packages[0]
+
+		// The hue goes from green (0.333) for packages without issues to red (0) for the worst one.
+		var hue float32 = 0.333
+		if maxRate > 0 {
+			hue *= 1 - rates[pkg.ImportPath]/maxRate
+		}
+
+		fmt.Fprintf(graph, "  %q [label=\"%s\", fillcolor=\"%.3f 0.500 1.000\"];\n", pkg.ImportPath, labels[pkg.ImportPath], hue)
+	}
+
+	for _, subView := range view.Packages {
+		pkg := subView.Packages[0]
+
+		cycle := map[string]bool{}
+		for _, importPath := range pkg.Cycle {
+			cycle[importPath] = true
+		}
+
+		for _, importPath := range pkg.Imports {
+			if cycle[importPath] {
+				fmt.Fprintf(graph, "  %q -> %q [color=red];\n", pkg.ImportPath, importPath)
+			} else {
+				fmt.Fprintf(graph, "  %q -> %q;\n", pkg.ImportPath, importPath)
+			}
+		}
+	}
+
+	graph.WriteString("}\n")
+
+	_, err := io.WriteString(w, graph.String())
+
+	return err
+}
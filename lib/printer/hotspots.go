@@ -0,0 +1,74 @@
+package printer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/Helcaraxan/goality/lib/analysis"
+	"github.com/Helcaraxan/goality/lib/printer/formatters"
+)
+
+type jsonHotspot struct {
+	Path         string  `json:"path"`
+	Score        float32 `json:"score"`
+	Commits      int     `json:"commits"`
+	ChangedLines int     `json:"changed_lines"`
+	LastModified string  `json:"last_modified"`
+	Issues       int     `json:"issues"`
+	IssueRate    float32 `json:"issue_rate"`
+}
+
+func PrintHotspots(w io.Writer, hotspots analysis.Hotspots, format FormatType) error {
+	if format == FormatTypeJSON {
+		output := []*jsonHotspot{}
+		for _, hotspot := range hotspots {
+			output = append(output, &jsonHotspot{
+				Path:         hotspot.Path,
+				Score:        hotspot.Score,
+				Commits:      hotspot.Commits,
+				ChangedLines: hotspot.ChangedLines,
+				LastModified: hotspot.LastModified.Format(dateFormat),
+				Issues:       hotspot.Issues,
+				IssueRate:    hotspot.IssueRate,
+			})
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(output)
+	}
+
+	var (
+		hotspotMatrix = [][]string{}
+		headers       = []string{"path", "score", "commits", "changed-lines", "last-modified", "issues"}
+	)
+
+	for _, hotspot := range hotspots {
+		hotspotMatrix = append(hotspotMatrix, []string{
+			hotspot.Path,
+			fmt.Sprintf("%.1f", hotspot.Score),
+			strconv.Itoa(hotspot.Commits),
+			strconv.Itoa(hotspot.ChangedLines),
+			hotspot.LastModified.Format(dateFormat),
+			strconv.Itoa(hotspot.Issues),
+			fmt.Sprintf("(%4.2f)", hotspot.IssueRate),
+		})
+	}
+
+	var formatter Formatter
+
+	switch format {
+	case FormatTypeCSV:
+		formatter = &formatters.CSVFormatter{}
+	case FormatTypeScreen:
+		formatter = &formatters.ScreenFormatter{}
+	default:
+		return errors.New("unknown format type specified for result printing")
+	}
+
+	return formatter.PrintTable(w, headers, hotspotMatrix, []int{1, 1, 1, 1, 1, 2})
+}
@@ -0,0 +1,103 @@
+package printer
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/Helcaraxan/goality/lib/printer/formatters"
+	"github.com/Helcaraxan/goality/lib/report"
+)
+
+type jsonImprovement struct {
+	Linter string `json:"linter"`
+	Before int    `json:"before"`
+	After  int    `json:"after"`
+	Fixed  int    `json:"fixed"`
+}
+
+// PrintImprovement compares the number of issues of each linter in two Views of the same project,
+// e.g. before and after applying fixes. The issues of all SubViews of each View are counted.
+func PrintImprovement(w io.Writer, before *report.View, after *report.View, format FormatType) error {
+	beforeCounts, afterCounts := countIssues(before), countIssues(after)
+
+	var linters []string
+	for linter := range beforeCounts {
+		linters = append(linters, linter)
+	}
+
+	for linter := range afterCounts {
+		if _, ok := beforeCounts[linter]; !ok {
+			linters = append(linters, linter)
+		}
+	}
+
+	sort.Strings(linters)
+
+	improvements := []*jsonImprovement{}
+	total := &jsonImprovement{Linter: "total"}
+
+	for _, linter := range linters {
+		improvement := &jsonImprovement{
+			Linter: linter,
+			Before: beforeCounts[linter],
+			After:  afterCounts[linter],
+			Fixed:  beforeCounts[linter] - afterCounts[linter],
+		}
+
+		improvements = append(improvements, improvement)
+
+		total.Before += improvement.Before
+		total.After += improvement.After
+		total.Fixed += improvement.Fixed
+	}
+
+	improvements = append(improvements, total)
+
+	if format == FormatTypeJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(improvements)
+	}
+
+	var (
+		improvementMatrix = [][]string{}
+		headers           = []string{"linter", "before", "after", "fixed"}
+	)
+
+	for _, improvement := range improvements {
+		improvementMatrix = append(improvementMatrix, []string{
+			improvement.Linter,
+			strconv.Itoa(improvement.Before),
+			strconv.Itoa(improvement.After),
+			strconv.Itoa(improvement.Fixed),
+		})
+	}
+
+	var formatter Formatter
+
+	switch format {
+	case FormatTypeCSV:
+		formatter = &formatters.CSVFormatter{}
+	case FormatTypeScreen:
+		formatter = &formatters.ScreenFormatter{}
+	default:
+		return errors.New("unknown format type specified for result printing")
+	}
+
+	return formatter.PrintTable(w, headers, improvementMatrix, []int{1, 1, 1, 1})
+}
+
+func countIssues(view *report.View) map[string]int {
+	counts := map[string]int{}
+	for _, linter := range view.Linters {
+		for _, subView := range view.SubViews {
+			counts[linter] += len(subView.Issues[linter])
+		}
+	}
+
+	return counts
+}
@@ -0,0 +1,265 @@
+package printer
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/Helcaraxan/goality/lib/analysis"
+	"github.com/Helcaraxan/goality/lib/report"
+)
+
+type jsonView struct {
+	Path       string   `json:"path"`
+	Target     string   `json:"target,omitempty"`
+	RateMetric string   `json:"rate_metric"`
+	Linters    []string `json:"linters"`
+	Excluded   []string `json:"excluded,omitempty"`
+	// MinSeverity is set when issues below a given severity were excluded.
+	MinSeverity string `json:"min_severity,omitempty"`
+	// ScoreModel and ScoreFormula document how the scores of the SubViews were derived.
+	ScoreModel   *analysis.ScoreModel `json:"score_model,omitempty"`
+	ScoreFormula string               `json:"score_formula,omitempty"`
+	// EffortFormula documents how the remediation efforts of the SubViews were estimated.
+	EffortFormula string         `json:"effort_formula,omitempty"`
+	SubViews      []*jsonSubView `json:"sub_views"`
+}
+
+type jsonSubView struct {
+	Path         string                     `json:"path,omitempty"`
+	Lines        int                        `json:"lines"`
+	Score        *jsonScore                 `json:"score,omitempty"`
+	Remediation  *jsonRemediation           `json:"remediation,omitempty"`
+	Issues       map[string]*jsonIssues     `json:"issues"`
+	Generated    *jsonGenerated             `json:"generated,omitempty"`
+	Suppressions *jsonSuppressions          `json:"suppressions,omitempty"`
+	Complexity   map[string]*jsonComplexity `json:"complexity,omitempty"`
+	Coverage     *jsonCoverage              `json:"coverage,omitempty"`
+	Churn        *jsonChurn                 `json:"churn,omitempty"`
+	Coupling     *jsonCoupling              `json:"coupling,omitempty"`
+	Severities   map[string]int             `json:"severities,omitempty"`
+	// In split test code mode the top-level values only cover production code.
+	Test *jsonSubView `json:"test,omitempty"`
+}
+
+type jsonScore struct {
+	Value         float64            `json:"value"`
+	Grade         string             `json:"grade"`
+	Density       float64            `json:"density"`
+	Contributions map[string]float64 `json:"contributions,omitempty"`
+}
+
+// jsonRemediation expresses efforts in minutes.
+type jsonRemediation struct {
+	Effort    float64            `json:"effort"`
+	DebtRatio float64            `json:"debt_ratio"`
+	PerLinter map[string]float64 `json:"per_linter,omitempty"`
+}
+
+type jsonIssues struct {
+	Count int     `json:"count"`
+	Rate  float32 `json:"rate"`
+}
+
+type jsonGenerated struct {
+	Files int `json:"files"`
+	Lines int `json:"lines"`
+}
+
+type jsonSuppressions struct {
+	Count            int            `json:"count"`
+	Density          float32        `json:"density"`
+	PerLinter        map[string]int `json:"per_linter"`
+	ExceedsThreshold bool           `json:"exceeds_threshold,omitempty"`
+}
+
+type jsonComplexity struct {
+	Threshold     int     `json:"threshold"`
+	Functions     int     `json:"functions"`
+	Mean          float32 `json:"mean"`
+	P90           int     `json:"p90"`
+	Max           int     `json:"max"`
+	OverThreshold int     `json:"over_threshold"`
+}
+
+type jsonCoverage struct {
+	Statements int     `json:"statements"`
+	Covered    int     `json:"covered"`
+	Percentage float32 `json:"percentage"`
+}
+
+type jsonChurn struct {
+	Commits      int    `json:"commits"`
+	Added        int    `json:"added"`
+	Removed      int    `json:"removed"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+type jsonCoupling struct {
+	Packages    int     `json:"packages"`
+	Afferent    int     `json:"afferent"`
+	Efferent    int     `json:"efferent"`
+	Instability float32 `json:"instability"`
+	MaxDepth    int     `json:"max_depth"`
+	InCycles    int     `json:"in_cycles"`
+}
+
+func printJSONView(w io.Writer, view *report.View, subViewList []string, opt *PrintOpts) error {
+	output := &jsonView{
+		Path:       view.Path,
+		RateMetric: view.RateMetric.String(),
+		Linters:    view.Linters,
+		Excluded:   view.Excluded,
+	}
+
+	if view.Target != nil {
+		output.Target = view.Target.String()
+	}
+
+	if view.MinSeverity > report.SeverityInfo {
+		output.MinSeverity = view.MinSeverity.String()
+	}
+
+	if opt.score != nil {
+		output.ScoreModel = opt.score
+		output.ScoreFormula = opt.score.Description(view.RateMetric)
+	}
+
+	if opt.effort != nil {
+		output.EffortFormula = opt.effort.Description(view.RateMetric)
+	}
+
+	for _, subViewPath := range subViewList {
+		subView := view.SubViews[subViewPath]
+
+		var jsonSubView *jsonSubView
+		if view.TestCode == report.TestCodeSplit {
+			jsonSubView = newJSONSubView(subView.Production(), view, opt)
+			if subView.Test != nil {
+				jsonSubView.Test = newJSONSubView(subView.Test, view, opt)
+			}
+		} else {
+			jsonSubView = newJSONSubView(subView, view, opt)
+		}
+
+		jsonSubView.Path = subView.Path
+		output.SubViews = append(output.SubViews, jsonSubView)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(output)
+}
+
+func newJSONSubView(subView *report.SubView, view *report.View, opt *PrintOpts) *jsonSubView {
+	output := &jsonSubView{
+		Lines:  subView.Lines(view.RateMetric),
+		Issues: map[string]*jsonIssues{},
+	}
+
+	if opt.score != nil {
+		score := opt.score.Score(view, subView)
+		output.Score = &jsonScore{
+			Value:         score.Value,
+			Grade:         score.Grade,
+			Density:       score.Density,
+			Contributions: score.Contributions,
+		}
+	}
+
+	if opt.effort != nil {
+		remediation := opt.effort.Remediation(view, subView)
+		output.Remediation = &jsonRemediation{
+			Effort:    remediation.Effort.Minutes(),
+			DebtRatio: remediation.DebtRatio,
+		}
+
+		for linter, effort := range remediation.PerLinter {
+			if output.Remediation.PerLinter == nil {
+				output.Remediation.PerLinter = map[string]float64{}
+			}
+
+			output.Remediation.PerLinter[linter] = effort.Minutes()
+		}
+	}
+
+	for _, linter := range view.Linters {
+		issueCount := len(subView.Issues[linter])
+		output.Issues[linter] = &jsonIssues{
+			Count: issueCount,
+			Rate:  subView.OccurrenceRate(issueCount, view.RateMetric),
+		}
+	}
+
+	if subView.GeneratedFileCount > 0 {
+		output.Generated = &jsonGenerated{Files: subView.GeneratedFileCount, Lines: subView.GeneratedLineCount}
+	}
+
+	if len(subView.Suppressions) > 0 || view.SuppressionThreshold > 0 {
+		output.Suppressions = &jsonSuppressions{
+			Count:            subView.SuppressionCount(),
+			Density:          subView.SuppressionDensity(view.RateMetric),
+			PerLinter:        subView.Suppressions,
+			ExceedsThreshold: view.ExceedsSuppressionThreshold(subView),
+		}
+	}
+
+	for _, metric := range opt.complexity {
+		if output.Complexity == nil {
+			output.Complexity = map[string]*jsonComplexity{}
+		}
+
+		threshold := view.ComplexityThreshold(metric)
+		stats := subView.Complexity(metric, threshold)
+
+		output.Complexity[metric.String()] = &jsonComplexity{
+			Threshold:     threshold,
+			Functions:     stats.Count,
+			Mean:          stats.Mean,
+			P90:           stats.P90,
+			Max:           stats.Max,
+			OverThreshold: stats.OverThreshold,
+		}
+	}
+
+	if subView.Coverage != nil {
+		output.Coverage = &jsonCoverage{
+			Statements: subView.Coverage.Statements,
+			Covered:    subView.Coverage.Covered,
+			Percentage: subView.Coverage.Percentage(),
+		}
+	}
+
+	if subView.Churn != nil {
+		output.Churn = &jsonChurn{
+			Commits: subView.Churn.Commits,
+			Added:   subView.Churn.Added,
+			Removed: subView.Churn.Removed,
+		}
+
+		if !subView.Churn.LastModified.IsZero() {
+			output.Churn.LastModified = subView.Churn.LastModified.Format(dateFormat)
+		}
+	}
+
+	if len(subView.Packages) > 0 {
+		stats := subView.Coupling()
+		output.Coupling = &jsonCoupling{
+			Packages:    stats.Packages,
+			Afferent:    stats.Afferent,
+			Efferent:    stats.Efferent,
+			Instability: stats.Instability,
+			MaxDepth:    stats.MaxDepth,
+			InCycles:    stats.InCycles,
+		}
+	}
+
+	if subView.Severities != nil {
+		output.Severities = map[string]int{}
+		for severity, count := range subView.SeverityCounts(view.Linters) {
+			output.Severities[severity.String()] = count
+		}
+	}
+
+	return output
+}
@@ -0,0 +1,55 @@
+package printer
+
+import (
+	"github.com/Helcaraxan/goality/lib/analysis"
+	"github.com/Helcaraxan/goality/lib/report"
+)
+
+// PrintOpts contains options for printing a View.
+type PrintOpts struct {
+	complexity []report.ComplexityMetric
+	score      *analysis.ScoreModel
+	effort     *analysis.EffortModel
+}
+
+// WithComplexity adds a column for each of the given complexity metrics, summarising their
+// distribution over the functions of each SubView.
+func WithComplexity(metrics ...report.ComplexityMetric) *PrintOpts {
+	return &PrintOpts{complexity: metrics}
+}
+
+// WithScore adds a column with the score and grade of each SubView as computed by the given model.
+func WithScore(model *analysis.ScoreModel) *PrintOpts {
+	return &PrintOpts{score: model}
+}
+
+// WithEffort adds a column with the estimated remediation effort and debt ratio of each SubView, or
+// of each IssueCategory, as computed by the given model.
+func WithEffort(model *analysis.EffortModel) *PrintOpts {
+	return &PrintOpts{effort: model}
+}
+
+func aggregatePrintOpts(opts ...*PrintOpts) *PrintOpts {
+	aggregate := &PrintOpts{}
+
+	seen := map[report.ComplexityMetric]bool{}
+
+	for _, opt := range opts {
+		if opt.score != nil {
+			aggregate.score = opt.score
+		}
+
+		if opt.effort != nil {
+			aggregate.effort = opt.effort
+		}
+
+		for _, metric := range opt.complexity {
+			if !seen[metric] {
+				seen[metric] = true
+				aggregate.complexity = append(aggregate.complexity, metric)
+			}
+		}
+	}
+
+	return aggregate
+}
@@ -0,0 +1,90 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/Helcaraxan/goality/lib/analysis"
+)
+
+type jsonOutlier struct {
+	Path       string  `json:"path"`
+	Linter     string  `json:"linter"`
+	Lines      int     `json:"lines"`
+	Issues     int     `json:"issues"`
+	Rate       float32 `json:"rate"`
+	Peers      int     `json:"peers"`
+	PeerMean   float64 `json:"peer_mean"`
+	PeerStdDev float64 `json:"peer_std_dev"`
+	// ZScore is omitted when it is infinite as JSON can not represent it.
+	ZScore     *float64 `json:"z_score,omitempty"`
+	Percentile float64  `json:"percentile"`
+}
+
+// PrintOutliers prints the given outliers. The criteria that were used to detect them are included in
+// the screen output.
+func PrintOutliers(w io.Writer, outliers analysis.Outliers, criteria *analysis.OutlierCriteria, format FormatType) error {
+	if format == FormatTypeJSON {
+		output := []*jsonOutlier{}
+		for _, outlier := range outliers {
+			output = append(output, &jsonOutlier{
+				Path:       outlier.Path,
+				Linter:     outlier.Linter,
+				Lines:      outlier.Lines,
+				Issues:     outlier.Issues,
+				Rate:       outlier.Rate,
+				Peers:      outlier.Peers,
+				PeerMean:   outlier.PeerMean,
+				PeerStdDev: outlier.PeerStdDev,
+				Percentile: outlier.Percentile,
+			})
+
+			if !math.IsInf(outlier.ZScore, 0) {
+				output[len(output)-1].ZScore = &outlier.ZScore
+			}
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(output)
+	}
+
+	var (
+		outlierMatrix = [][]string{}
+		headers       = []string{"path", "linter", "lines", "issues", "peer-mean", "z-score", "percentile"}
+	)
+
+	for _, outlier := range outliers {
+		outlierMatrix = append(outlierMatrix, []string{
+			outlier.Path,
+			outlier.Linter,
+			strconv.Itoa(outlier.Lines),
+			strconv.Itoa(outlier.Issues),
+			fmt.Sprintf("(%4.2f)", outlier.Rate),
+			fmt.Sprintf("%.2f", outlier.PeerMean),
+			fmt.Sprintf("%.1f", outlier.ZScore),
+			fmt.Sprintf("%.0f", outlier.Percentile),
+		})
+	}
+
+	formatter, err := tableFormatter(format)
+	if err != nil {
+		return err
+	}
+
+	if err = formatter.PrintTable(w, headers, outlierMatrix, []int{1, 1, 1, 2, 1, 1, 1}); err != nil {
+		return err
+	}
+
+	if format != FormatTypeScreen || criteria == nil {
+		return nil
+	}
+
+	_, err = fmt.Fprintf(w, "\nData-format: issues (average issues per 1K lines)\nOutliers: %s\n", criteria)
+
+	return err
+}
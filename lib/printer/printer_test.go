@@ -3,12 +3,15 @@ package printer
 import (
 	"fmt"
 	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/golangci/golangci-lint/pkg/result"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -30,6 +33,7 @@ foo/dir/...    11  0  (0.00) 1 (90.91)
 foo/non-go/... 0   0  (0.00) 0 (0.00)   
 
 Data-format: total-issues (average issues per 1K LoC)
+Excluded paths: bar/my_exclude, vendor
 `, project.Path)
 
 	view := project.GenerateView(report.WithDepth(2))
@@ -39,19 +43,410 @@ Data-format: total-issues (average issues per 1K LoC)
 	assert.Equal(t, expectedOutput, w.String())
 }
 
+func Test_PrintViewSplitTestCode(t *testing.T) {
+	project := testProject(t)
+
+	expectedOutput := `path,LoC,,typecheck,,,,unused,,,
+./...,47,0,0,0.00,0,0.00,2,42.55,0,0.00
+`
+
+	view := project.GenerateView(report.WithTestCode(report.TestCodeSplit))
+
+	w := &strings.Builder{}
+	require.NoError(t, PrintView(w, view, FormatTypeCSV))
+	assert.Equal(t, expectedOutput, w.String())
+}
+
+func Test_PrintViewSuppressionThreshold(t *testing.T) {
+	project := testProject(t)
+
+	expectedOutput := `path,LoC,nolint,,typecheck,,unused,
+./...,47,0,0.00,0,0.00,2,42.55
+`
+
+	view := project.GenerateView(report.WithSuppressionThreshold(5))
+
+	w := &strings.Builder{}
+	require.NoError(t, PrintView(w, view, FormatTypeCSV))
+	assert.Equal(t, expectedOutput, w.String())
+}
+
+func Test_PrintViewComplexity(t *testing.T) {
+	project := testProject(t)
+
+	expectedOutput := `path,LoC,cyclomatic,,,,length,,,,typecheck,,unused,
+./...,47,2.2,5,5,0,7.8,21,21,1,0,0.00,2,42.55
+`
+
+	view := project.GenerateView(report.WithComplexityThreshold(report.ComplexityLength, 20))
+
+	w := &strings.Builder{}
+	require.NoError(t, PrintView(w, view, FormatTypeCSV, WithComplexity(report.ComplexityCyclomatic, report.ComplexityLength)))
+	assert.Equal(t, expectedOutput, w.String())
+}
+
+func Test_PrintViewScore(t *testing.T) {
+	project := testProject(t)
+
+	expectedOutput := `path,LoC,,score,,,,typecheck,,,,unused,,,
+./...,47,0,1.6,F,100.0,A,0,0.00,0,0.00,2,42.55,0,0.00
+`
+
+	model := analysis.DefaultScoreModel()
+	model.HalfDensity = 5
+	model.Caps = map[string]float64{"unused": 30}
+
+	view := project.GenerateView(report.WithTestCode(report.TestCodeSplit))
+
+	w := &strings.Builder{}
+	require.NoError(t, PrintView(w, view, FormatTypeCSV, WithScore(model)))
+	assert.Equal(t, expectedOutput, w.String())
+}
+
+func Test_PrintViewEffort(t *testing.T) {
+	project := testProject(t)
+
+	expectedOutput := `path,LoC,remediation,,typecheck,,unused,
+./...,47,20m,1.42%,0,0.00,2,42.55
+`
+
+	w := &strings.Builder{}
+	require.NoError(t, PrintView(w, project.GenerateView(), FormatTypeCSV, WithEffort(analysis.DefaultEffortModel())))
+	assert.Equal(t, expectedOutput, w.String())
+}
+
+func Test_PrintViewSeverities(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	mappingFile, err := ioutil.TempFile("", "goality-severities")
+	require.NoError(t, err)
+	defer os.Remove(mappingFile.Name())
+
+	_, err = mappingFile.WriteString("linters:\n  unused: error\n")
+	require.NoError(t, err)
+	require.NoError(t, mappingFile.Close())
+
+	project, err := report.Parse(
+		logger,
+		filepath.Join(wd, "..", "report", "testdata", "project"),
+		report.WithLinters("unused", "typecheck"),
+		report.WithExcludeDirs("my_exclude"),
+		report.WithSeverities(mappingFile.Name()),
+	)
+	require.NoError(t, err)
+
+	expectedOutput := `path,LoC,severity,,,typecheck,,unused,
+./...,47,2,0,0,0,0.00,2,42.55
+`
+
+	w := &strings.Builder{}
+	require.NoError(t, PrintView(w, project.GenerateView(), FormatTypeCSV))
+	assert.Equal(t, expectedOutput, w.String())
+}
+
+func Test_PrintViewJSON(t *testing.T) {
+	project := testProject(t)
+
+	expectedOutput := fmt.Sprintf(`{
+  "path": %q,
+  "rate_metric": "LoC",
+  "linters": [
+    "typecheck",
+    "unused"
+  ],
+  "excluded": [
+    "bar/my_exclude",
+    "vendor"
+  ],
+  "sub_views": [
+    {
+      "path": "./...",
+      "lines": 47,
+      "issues": {
+        "typecheck": {
+          "count": 0,
+          "rate": 0
+        },
+        "unused": {
+          "count": 2,
+          "rate": 42.553192
+        }
+      },
+      "complexity": {
+        "nesting": {
+          "threshold": 4,
+          "functions": 5,
+          "mean": 0.8,
+          "p90": 2,
+          "max": 2,
+          "over_threshold": 0
+        }
+      }
+    }
+  ]
+}
+`, project.Path)
+
+	view := project.GenerateView()
+
+	w := &strings.Builder{}
+	require.NoError(t, PrintView(w, view, FormatTypeJSON, WithComplexity(report.ComplexityNesting)))
+	assert.Equal(t, expectedOutput, w.String())
+}
+
+func Test_PrintHotspots(t *testing.T) {
+	hotspots := analysis.Hotspots{
+		{
+			Path:         "bar",
+			Score:        100,
+			Commits:      12,
+			ChangedLines: 340,
+			LastModified: time.Date(2020, 5, 20, 0, 0, 0, 0, time.UTC),
+			Issues:       3,
+			IssueRate:    25,
+		},
+		{
+			Path:         "foo/...",
+			Score:        12.5,
+			Commits:      3,
+			ChangedLines: 45,
+			LastModified: time.Date(2019, 11, 2, 0, 0, 0, 0, time.UTC),
+			Issues:       1,
+			IssueRate:    12.5,
+		},
+	}
+
+	expectedOutput := `path    score commits changed-lines last-modified issues    
+bar     100.0 12      340           2020-05-20    3 (25.00) 
+foo/... 12.5  3       45            2019-11-02    1 (12.50) 
+`
+
+	w := &strings.Builder{}
+	require.NoError(t, PrintHotspots(w, hotspots, FormatTypeScreen))
+	assert.Equal(t, expectedOutput, w.String())
+}
+
+func Test_PrintOutliers(t *testing.T) {
+	outliers := analysis.Outliers{
+		{
+			Path:       "lib/parser",
+			Linter:     analysis.AllLinters,
+			Lines:      420,
+			Issues:     21,
+			Rate:       50,
+			Peers:      12,
+			PeerMean:   8.25,
+			PeerStdDev: 12,
+			ZScore:     3.48,
+			Percentile: 100,
+		},
+		{
+			Path:       "lib/parser",
+			Linter:     "errcheck",
+			Lines:      420,
+			Issues:     9,
+			Rate:       21.43,
+			Peers:      12,
+			PeerMean:   2.1,
+			PeerStdDev: 6.4,
+			ZScore:     3.02,
+			Percentile: 100,
+		},
+	}
+
+	expectedOutput := `path       linter   lines issues     peer-mean z-score percentile 
+lib/parser all      420   21 (50.00) 8.25      3.5     100        
+lib/parser errcheck 420   9  (21.43) 2.10      3.0     100        
+
+Data-format: issues (average issues per 1K lines)
+Outliers: z-score above 2.0 among peers of comparable size with at least 50 lines
+`
+
+	w := &strings.Builder{}
+	require.NoError(t, PrintOutliers(w, outliers, analysis.DefaultOutlierCriteria(), FormatTypeScreen))
+	assert.Equal(t, expectedOutput, w.String())
+
+	expectedOutput = `path,linter,lines,issues,,peer-mean,z-score,percentile
+lib/parser,all,420,21,50.00,8.25,3.5,100
+lib/parser,errcheck,420,9,21.43,2.10,3.0,100
+`
+
+	w = &strings.Builder{}
+	require.NoError(t, PrintOutliers(w, outliers, analysis.DefaultOutlierCriteria(), FormatTypeCSV))
+	assert.Equal(t, expectedOutput, w.String())
+
+	// Infinite z-scores, when all peers share the same rate, can not be represented in JSON.
+	outliers[1].ZScore = math.Inf(1)
+	w = &strings.Builder{}
+	require.NoError(t, PrintOutliers(w, outliers, analysis.DefaultOutlierCriteria(), FormatTypeJSON))
+	assert.Equal(t, 1, strings.Count(w.String(), `"z_score"`))
+}
+
+func Test_PrintPackageGraph(t *testing.T) {
+	var (
+		cmd   = &report.Package{ImportPath: "example.com/cmd", Path: "cmd", Imports: []string{"example.com/store"}}
+		store = &report.Package{ImportPath: "example.com/store", Path: "store", Imports: []string{"example.com/cache"}, Cycle: []string{"example.com/cache", "example.com/store"}}
+		cache = &report.Package{ImportPath: "example.com/cache", Path: "cache", Imports: []string{"example.com/store"}, Cycle: []string{"example.com/cache", "example.com/store"}}
+	)
+
+	view := &report.View{
+		Linters: []string{"unused"},
+		Packages: []*report.SubView{
+			{Path: "cache", LineCount: 100, Packages: []*report.Package{cache}},
+			{Path: "cmd", LineCount: 100, Packages: []*report.Package{cmd}, Issues: map[string][]*result.Issue{"unused": {{}}}},
+			{Path: "store", LineCount: 50, Packages: []*report.Package{store}, Issues: map[string][]*result.Issue{"unused": {{}, {}}}},
+		},
+	}
+
+	expectedOutput := `digraph packages {
+  node [shape=box, style=filled];
+  "example.com/cache" [label="cache\n0 issues (0.00)", fillcolor="0.333 0.500 1.000"];
+  "example.com/cmd" [label="cmd\n1 issues (10.00)", fillcolor="0.250 0.500 1.000"];
+  "example.com/store" [label="store\n2 issues (40.00)", fillcolor="0.000 0.500 1.000"];
+  "example.com/cache" -> "example.com/store" [color=red];
+  "example.com/cmd" -> "example.com/store";
+  "example.com/store" -> "example.com/cache" [color=red];
+}
+`
+
+	w := &strings.Builder{}
+	require.NoError(t, PrintPackageGraph(w, view))
+	assert.Equal(t, expectedOutput, w.String())
+}
+
 func Test_PrintCategories(t *testing.T) {
 	project := testProject(t)
 
 	expectedOutput := `occurrences linter issue                
-2           unused func  <i....s unused 
+2           unused func <id....s unused 
 `
 
+	defer func(width int) { maxIssueTextWidth = width }(maxIssueTextWidth)
 	maxIssueTextWidth = 20
-	categories := analysis.IssueRanking(project.GenerateView(), 0)
+	categories := analysis.IssueRanking(project.GenerateView(), 0, nil)
 
 	w := &strings.Builder{}
 	require.NoError(t, PrintCategories(w, categories, FormatTypeScreen))
 	assert.Equal(t, expectedOutput, w.String())
+
+	expectedOutput = `occurrences,linter,issue,effort
+2,unused,func <id....s unused,20m
+`
+
+	w = &strings.Builder{}
+	require.NoError(t, PrintCategories(w, categories, FormatTypeCSV, WithEffort(analysis.DefaultEffortModel())))
+	assert.Equal(t, expectedOutput, w.String())
+}
+
+func Test_PrintCategoryOccurrences(t *testing.T) {
+	categories := analysis.IssueRanking(testProject(t).GenerateView(), 0, nil)
+
+	expectedOutput := `unused: func <identifier> is unused (2 occurrences)
+
+directory location           issue                       
+bar       bar/file.go:3      func ` + "`unusedFunc`" + ` is unused 
+foo/dir   foo/dir/file.go:14 func ` + "`unworthy`" + ` is unused   
+`
+
+	w := &strings.Builder{}
+	require.NoError(t, PrintCategoryOccurrences(w, categories, FormatTypeScreen))
+	assert.Equal(t, expectedOutput, w.String())
+
+	expectedOutput = `category,directory,file,line,issue
+unused: func <identifier> is unused,bar,bar/file.go,3,func ` + "`unusedFunc`" + ` is unused
+unused: func <identifier> is unused,foo/dir,foo/dir/file.go,14,func ` + "`unworthy`" + ` is unused
+`
+
+	w = &strings.Builder{}
+	require.NoError(t, PrintCategoryOccurrences(w, categories, FormatTypeCSV))
+	assert.Equal(t, expectedOutput, w.String())
+}
+
+func Test_PrintCategoryMatrix(t *testing.T) {
+	project := testProject(t)
+	categories := analysis.IssueRanking(project.GenerateView(), 0, nil)
+	matrix := analysis.NewCategoryMatrix(project.GenerateView(report.WithDepth(1)), categories, 0)
+
+	expectedOutput := `path    #1      
+bar/... 1 (50%) 
+foo/... 1 (50%) 
+
+Data-format: occurrences (share of the category's occurrences)
+#1: unused: func <identifier> is unused (2 occurrences)
+`
+
+	w := &strings.Builder{}
+	require.NoError(t, PrintCategoryMatrix(w, matrix, FormatTypeScreen))
+	assert.Equal(t, expectedOutput, w.String())
+
+	expectedOutput = `[
+  {
+    "key": "unused: func \u003cidentifier\u003e is unused",
+    "linter": "unused",
+    "template": "func \u003cidentifier\u003e is unused",
+    "occurrences": 2,
+    "paths": {
+      "bar/...": 1,
+      "foo/...": 1
+    }
+  }
+]
+`
+
+	w = &strings.Builder{}
+	require.NoError(t, PrintCategoryMatrix(w, matrix, FormatTypeJSON))
+	assert.Equal(t, expectedOutput, w.String())
+}
+
+func Test_PrintImprovement(t *testing.T) {
+	before := testProject(t).GenerateView()
+	after := &report.View{
+		Linters: []string{"typecheck", "unused"},
+		SubViews: map[string]*report.SubView{
+			"./...": {Path: "./...", Issues: map[string][]*result.Issue{"unused": {{FromLinter: "unused"}}}},
+		},
+	}
+
+	expectedOutput := `linter,before,after,fixed
+typecheck,0,0,0
+unused,2,1,1
+total,2,1,1
+`
+
+	w := &strings.Builder{}
+	require.NoError(t, PrintImprovement(w, before, after, FormatTypeCSV))
+	assert.Equal(t, expectedOutput, w.String())
+}
+
+func Test_Progress(t *testing.T) {
+	progress := NewProgress(&strings.Builder{})
+
+	for _, event := range []*report.Event{
+		{Type: report.EventTypeDirectoryParsed, Path: "foo"},
+		{Type: report.EventTypeDirectoryParsed, Path: "."},
+		{Type: report.EventTypeLintQueued, Path: "."},
+		{Type: report.EventTypeLinterStarted, Path: "./..."},
+		{Type: report.EventTypeLinterKilled, Path: "./..."},
+		{Type: report.EventTypeLinterStarted, Path: "."},
+		{Type: report.EventTypeIssuesRegistered, Path: ".", IssueCount: 2},
+		{Type: report.EventTypeLintQueued, Path: "foo"},
+		{Type: report.EventTypeLintQueued, Path: "bar"},
+		{Type: report.EventTypeLinterStarted, Path: "foo/..."},
+		{Type: report.EventTypeIssuesRegistered, Path: "foo/...", IssueCount: 1},
+		{Type: report.EventTypeLinterStarted, Path: "bar/..."},
+	} {
+		progress.Handle(event)
+	}
+
+	assert.Equal(
+		t,
+		"[1m30s] parsed 2 directories - sub-trees: 1 pending, 1 completed, 1 split - memory: 1.0 GiB (10%) - linting bar/...",
+		progress.render(90*time.Second+300*time.Millisecond, "1.0 GiB (10%)"),
+	)
 }
 
 var (
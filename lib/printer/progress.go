@@ -0,0 +1,144 @@
+package printer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/mem"
+
+	"github.com/Helcaraxan/goality/lib/report"
+)
+
+var progressRefreshInterval = 500 * time.Millisecond
+
+// Progress renders a continuously updated single-line summary of an ongoing analysis based on the
+// events emitted by report.Parse. It is intended for interactive terminals only.
+type Progress struct {
+	w     io.Writer
+	start time.Time
+
+	lock         sync.Mutex
+	parsedDirs   int
+	queued       int
+	completed    int
+	split        int
+	current      string
+	lastLineSize int
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// IsTerminal reports whether the given file is attached to an interactive terminal.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func NewProgress(w io.Writer) *Progress {
+	return &Progress{w: w}
+}
+
+// Handle registers the given event. It can be passed to report.WithEventHandler.
+func (p *Progress) Handle(event *report.Event) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	switch event.Type {
+	case report.EventTypeDirectoryParsed:
+		p.parsedDirs++
+	case report.EventTypeLintQueued:
+		p.queued++
+	case report.EventTypeLinterStarted:
+		p.current = event.Path
+	case report.EventTypeLinterKilled:
+		if strings.HasSuffix(event.Path, "/...") {
+			p.split++
+		}
+	case report.EventTypeIssuesRegistered:
+		if strings.HasSuffix(event.Path, "/...") {
+			p.completed++
+		}
+	default: // Nothing.
+	}
+}
+
+// Start begins the periodic rendering of the progress line.
+func (p *Progress) Start() {
+	p.start = time.Now()
+	p.stop = make(chan struct{})
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(progressRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.refresh()
+			}
+		}
+	}()
+}
+
+// Stop halts the rendering and clears the progress line so that subsequent output is unaffected.
+func (p *Progress) Stop() {
+	close(p.stop)
+	p.wg.Wait()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	_, _ = fmt.Fprintf(p.w, "\r%s\r", strings.Repeat(" ", p.lastLineSize))
+}
+
+func (p *Progress) refresh() {
+	memory := "n/a"
+	if memStat, err := mem.VirtualMemory(); err == nil {
+		memory = fmt.Sprintf("%.1f GiB (%.0f%%)", float64(memStat.Used)/(1<<30), memStat.UsedPercent)
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	line := p.render(time.Since(p.start), memory)
+
+	padding := ""
+	if len(line) < p.lastLineSize {
+		padding = strings.Repeat(" ", p.lastLineSize-len(line))
+	}
+
+	p.lastLineSize = len(line)
+
+	_, _ = fmt.Fprintf(p.w, "\r%s%s", line, padding)
+}
+
+func (p *Progress) render(elapsed time.Duration, memory string) string {
+	line := fmt.Sprintf(
+		"[%s] parsed %d directories - sub-trees: %d pending, %d completed, %d split - memory: %s",
+		elapsed.Truncate(time.Second),
+		p.parsedDirs,
+		p.queued-p.completed-p.split,
+		p.completed,
+		p.split,
+		memory,
+	)
+	if p.current != "" {
+		line += " - linting " + p.current
+	}
+
+	return line
+}
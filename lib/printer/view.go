@@ -9,51 +9,120 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Helcaraxan/goality/lib/analysis"
 	"github.com/Helcaraxan/goality/lib/printer/formatters"
 	"github.com/Helcaraxan/goality/lib/report"
 )
 
 type FormatType uint8
 
+// dateFormat is used to print dates such as the last modification of files.
+const dateFormat = "2006-01-02"
+
 const (
 	FormatTypeUnknown = iota
 	FormatTypeScreen
 	FormatTypeCSV
+	FormatTypeJSON
 )
 
 type Formatter interface {
 	PrintTable(io.Writer, []string, [][]string, []int) error
 }
 
-func PrintView(w io.Writer, view *report.View, format FormatType) error {
+func PrintView(w io.Writer, view *report.View, format FormatType, opts ...*PrintOpts) error {
 	if len(view.SubViews) == 0 {
 		return nil
 	}
 
-	var subViewList []string
+	opt := aggregatePrintOpts(opts...)
+
+	subViewList := sortedSubViewPaths(view)
+
+	if format == FormatTypeJSON {
+		return printJSONView(w, view, subViewList, opt)
+	}
+
+	// When splitting test code from production code each value is printed twice, first for the
+	// production code and then for the test code.
+	segmentCount := 1
+	if view.TestCode == report.TestCodeSplit {
+		segmentCount = 2
+	}
+
+	// Only report on skipped generated code, suppressed issues, coverage, churn, packages and
+	// severities if there are any.
+	columns := optionalColumns{
+		suppressions:  view.SuppressionThreshold > 0,
+		markThreshold: view.SuppressionThreshold > 0 && format == FormatTypeScreen,
+		complexity:    opt.complexity,
+	}
+
 	for _, subView := range view.SubViews {
-		subViewList = append(subViewList, subView.Path)
+		columns.generated = columns.generated || subView.GeneratedFileCount > 0
+		columns.suppressions = columns.suppressions || len(subView.Suppressions) > 0
+		columns.coverage = columns.coverage || subView.Coverage != nil
+		columns.churn = columns.churn || subView.Churn != nil
+		columns.coupling = columns.coupling || len(subView.Packages) > 0
+		columns.severity = columns.severity || subView.Severities != nil
 	}
 
-	sort.Slice(subViewList, func(i, j int) bool {
-		iDepth := strings.Count(subViewList[i], string(os.PathSeparator))
-		jDepth := strings.Count(subViewList[j], string(os.PathSeparator))
-		if iDepth != jDepth {
-			return iDepth < jDepth
-		}
-		return subViewList[i] < subViewList[j]
-	})
+	headers := []string{"path", view.RateMetric.String()}
+	ratios := []int{1, segmentCount}
 
-	ratios := []int{1, 1}
-	for i := 0; i < len(view.Linters); i++ {
+	if opt.score != nil {
+		headers = append(headers, "score")
+		ratios = append(ratios, 2*segmentCount)
+	}
+
+	if opt.effort != nil {
+		headers = append(headers, "remediation")
+		ratios = append(ratios, 2*segmentCount)
+	}
+
+	if columns.generated {
+		headers = append(headers, "generated")
+		ratios = append(ratios, 2)
+	}
+
+	if columns.suppressions {
+		headers = append(headers, "nolint")
+		ratios = append(ratios, 2)
+	}
+
+	for _, metric := range columns.complexity {
+		headers = append(headers, metric.String())
+		ratios = append(ratios, 4)
+	}
+
+	if columns.coverage {
+		headers = append(headers, "coverage")
 		ratios = append(ratios, 2)
 	}
 
-	headers := append([]string{"path", "LoC"}, view.Linters...)
+	if columns.churn {
+		headers = append(headers, "churn")
+		ratios = append(ratios, 4)
+	}
+
+	if columns.coupling {
+		headers = append(headers, "coupling")
+		ratios = append(ratios, 5)
+	}
+
+	if columns.severity {
+		headers = append(headers, "severity")
+		ratios = append(ratios, 3)
+	}
+
+	headers = append(headers, view.Linters...)
+	for i := 0; i < len(view.Linters); i++ {
+		ratios = append(ratios, 2*segmentCount)
+	}
 
 	resultMatrix := [][]string{}
 	for _, subViewPath := range subViewList {
-		resultMatrix = append(resultMatrix, getSubViewLine(view.SubViews[subViewPath], view.Linters))
+		resultMatrix = append(resultMatrix, getSubViewLine(view.SubViews[subViewPath], view, columns, opt))
 	}
 
 	var formatter Formatter
@@ -64,7 +133,12 @@ func PrintView(w io.Writer, view *report.View, format FormatType) error {
 	case FormatTypeCSV:
 		formatter = &formatters.CSVFormatter{}
 	case FormatTypeScreen:
-		if _, err := fmt.Fprintf(w, "Quality report for Go codebase located at '%s'\n\n", view.Path); err != nil {
+		var target string
+		if view.Target != nil {
+			target = fmt.Sprintf(" for target %s", view.Target)
+		}
+
+		if _, err := fmt.Fprintf(w, "Quality report for Go codebase located at '%s'%s\n\n", view.Path, target); err != nil {
 			return err
 		}
 
@@ -77,27 +151,241 @@ func PrintView(w io.Writer, view *report.View, format FormatType) error {
 
 	switch format {
 	case FormatTypeScreen:
-		if _, err := fmt.Fprint(w, "\nData-format: total-issues (average issues per 1K LoC)\n"); err != nil {
+		dataFormat := fmt.Sprintf("total-issues (average issues per 1K %s)", view.RateMetric)
+		if view.TestCode == report.TestCodeSplit {
+			dataFormat = "production " + dataFormat + " followed by test " + dataFormat
+		}
+
+		if _, err := fmt.Fprintf(w, "\nData-format: %s\n", dataFormat); err != nil {
 			return err
 		}
+
+		if opt.score != nil {
+			if _, err := fmt.Fprintf(w, "%s\n", opt.score.Description(view.RateMetric)); err != nil {
+				return err
+			}
+		}
+
+		if opt.effort != nil {
+			if _, err := fmt.Fprintf(w, "%s\n", opt.effort.Description(view.RateMetric)); err != nil {
+				return err
+			}
+		}
+
+		if columns.generated {
+			if _, err := fmt.Fprint(w, "Generated code was excluded: files (LoC)\n"); err != nil {
+				return err
+			}
+		}
+
+		if columns.suppressions {
+			if _, err := fmt.Fprintf(w, "Suppressions: nolint-directives (average directives per 1K %s)\n", view.RateMetric); err != nil {
+				return err
+			}
+		}
+
+		if len(columns.complexity) > 0 {
+			var thresholds []string
+			for _, metric := range columns.complexity {
+				thresholds = append(thresholds, fmt.Sprintf("%s > %d", metric, view.ComplexityThreshold(metric)))
+			}
+
+			if _, err := fmt.Fprintf(w, "Complexity: mean p90 max (functions over threshold: %s)\n", strings.Join(thresholds, ", ")); err != nil {
+				return err
+			}
+		}
+
+		if columns.coverage {
+			if _, err := fmt.Fprint(w, "Coverage: percentage-of-statements-covered (statements)\n"); err != nil {
+				return err
+			}
+		}
+
+		if columns.churn {
+			if _, err := fmt.Fprint(w, "Churn: commits +lines-added -lines-removed last-modified\n"); err != nil {
+				return err
+			}
+		}
+
+		if columns.coupling {
+			if _, err := fmt.Fprint(w, "Coupling: afferent efferent instability import-depth (packages-in-cycles)\n"); err != nil {
+				return err
+			}
+		}
+
+		if columns.severity {
+			if _, err := fmt.Fprint(w, "Severity: errors warnings infos\n"); err != nil {
+				return err
+			}
+		}
+
+		if view.MinSeverity > report.SeverityInfo {
+			if _, err := fmt.Fprintf(w, "Issues with a severity below '%s' were excluded\n", view.MinSeverity); err != nil {
+				return err
+			}
+		}
+
+		if view.SuppressionThreshold > 0 {
+			if _, err := fmt.Fprintf(w, "Suppressions marked with '!' exceed the threshold of %.2f per 1K %s\n", view.SuppressionThreshold, view.RateMetric); err != nil {
+				return err
+			}
+		}
+
+		if len(view.Excluded) > 0 {
+			if _, err := fmt.Fprintf(w, "Excluded paths: %s\n", strings.Join(view.Excluded, ", ")); err != nil {
+				return err
+			}
+		}
 	default: // Nothing.
 	}
 
 	return nil
 }
 
-func getSubViewLine(subView *report.SubView, linters []string) []string {
-	results := []string{subView.Path, strconv.Itoa(subView.LineCount)}
+// optionalColumns determines which of the columns that are not always present are printed.
+type optionalColumns struct {
+	generated     bool
+	suppressions  bool
+	markThreshold bool
+	complexity    []report.ComplexityMetric
+	coverage      bool
+	churn         bool
+	coupling      bool
+	severity      bool
+}
+
+// sortedSubViewPaths returns the paths of the View's SubViews, ordered by depth and then by name.
+func sortedSubViewPaths(view *report.View) []string {
+	var subViewList []string
+	for _, subView := range view.SubViews {
+		subViewList = append(subViewList, subView.Path)
+	}
+
+	sort.Slice(subViewList, func(i, j int) bool {
+		iDepth := strings.Count(subViewList[i], string(os.PathSeparator))
+		jDepth := strings.Count(subViewList[j], string(os.PathSeparator))
+		if iDepth != jDepth {
+			return iDepth < jDepth
+		}
+		return subViewList[i] < subViewList[j]
+	})
+
+	return subViewList
+}
+
+func getSubViewLine(subView *report.SubView, view *report.View, columns optionalColumns, opt *PrintOpts) []string {
+	segments := []*report.SubView{subView}
+
+	if view.TestCode == report.TestCodeSplit {
+		test := subView.Test
+		if test == nil {
+			test = &report.SubView{}
+		}
+
+		segments = []*report.SubView{subView.Production(), test}
+	}
+
+	results := []string{subView.Path}
+	for _, segment := range segments {
+		results = append(results, strconv.Itoa(segment.Lines(view.RateMetric)))
+	}
+
+	if opt.score != nil {
+		for _, segment := range segments {
+			score := opt.score.Score(view, segment)
+			results = append(results, fmt.Sprintf("%.1f", score.Value), score.Grade)
+		}
+	}
+
+	if opt.effort != nil {
+		for _, segment := range segments {
+			remediation := opt.effort.Remediation(view, segment)
+			results = append(results, analysis.FormatEffort(remediation.Effort), fmt.Sprintf("(%.2f%%)", 100*remediation.DebtRatio))
+		}
+	}
+
+	if columns.generated {
+		results = append(results, strconv.Itoa(subView.GeneratedFileCount), fmt.Sprintf("(%d)", subView.GeneratedLineCount))
+	}
+
+	if columns.suppressions {
+		density := fmt.Sprintf("(%4.2f)", subView.SuppressionDensity(view.RateMetric))
+		if columns.markThreshold && view.ExceedsSuppressionThreshold(subView) {
+			density += "!"
+		}
+
+		results = append(results, strconv.Itoa(subView.SuppressionCount()), density)
+	}
 
-	for _, linter := range linters {
-		issueCount := len(subView.Issues[linter])
-		occurenceRate := float32(0)
+	for _, metric := range columns.complexity {
+		stats := subView.Complexity(metric, view.ComplexityThreshold(metric))
+		results = append(
+			results,
+			fmt.Sprintf("%.1f", stats.Mean),
+			strconv.Itoa(stats.P90),
+			strconv.Itoa(stats.Max),
+			fmt.Sprintf("(%d)", stats.OverThreshold),
+		)
+	}
+
+	if columns.coverage {
+		var statements int
+		if subView.Coverage != nil {
+			statements = subView.Coverage.Statements
+		}
+
+		results = append(results, fmt.Sprintf("%.1f", subView.Coverage.Percentage()), fmt.Sprintf("(%d)", statements))
+	}
+
+	if columns.churn {
+		churn := subView.Churn
+		if churn == nil {
+			churn = &report.Churn{}
+		}
 
-		if issueCount > 0 {
-			occurenceRate = 1000 * float32(issueCount) / float32(subView.LineCount)
+		var lastModified string
+		if !churn.LastModified.IsZero() {
+			lastModified = churn.LastModified.Format(dateFormat)
 		}
 
-		results = append(results, fmt.Sprintf("%d", issueCount), fmt.Sprintf("(%4.2f)", occurenceRate))
+		results = append(
+			results,
+			strconv.Itoa(churn.Commits),
+			fmt.Sprintf("+%d", churn.Added),
+			fmt.Sprintf("-%d", churn.Removed),
+			lastModified,
+		)
+	}
+
+	if columns.coupling {
+		stats := subView.Coupling()
+		results = append(
+			results,
+			strconv.Itoa(stats.Afferent),
+			strconv.Itoa(stats.Efferent),
+			fmt.Sprintf("%.2f", stats.Instability),
+			strconv.Itoa(stats.MaxDepth),
+			fmt.Sprintf("(%d)", stats.InCycles),
+		)
+	}
+
+	if columns.severity {
+		counts := subView.SeverityCounts(view.Linters)
+		results = append(
+			results,
+			strconv.Itoa(counts[report.SeverityError]),
+			strconv.Itoa(counts[report.SeverityWarning]),
+			strconv.Itoa(counts[report.SeverityInfo]),
+		)
+	}
+
+	for _, linter := range view.Linters {
+		for _, segment := range segments {
+			issueCount := len(segment.Issues[linter])
+			occurenceRate := segment.OccurrenceRate(issueCount, view.RateMetric)
+
+			results = append(results, fmt.Sprintf("%d", issueCount), fmt.Sprintf("(%4.2f)", occurenceRate))
+		}
 	}
 
 	return results
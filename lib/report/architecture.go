@@ -0,0 +1,168 @@
+package report
+
+import (
+	"fmt"
+	goparser "go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/golangci/golangci-lint/pkg/result"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// ArchitectureLinter is the name under which violations of architecture rules are reported.
+const ArchitectureLinter = "goality-arch"
+
+// architectureRules is the content of a rules file which restricts the imports between the project's
+// packages. Packages are designated by glob patterns matching the relative paths of their directories,
+// e.g.:
+//
+//	rules:
+//	  - name: independent-domain
+//	    from: ["internal/domain/**"]
+//	    deny: ["internal/transport/**", "internal/storage/**"]
+//	  - from: ["internal/transport/**"]
+//	    allow: ["internal/domain/**", "internal/transport/**"]
+//
+// The packages matching 'from' may not import any of the project's packages that match 'deny' nor,
+// when 'allow' is specified, any that do not match 'allow'.
+type architectureRules struct {
+	Rules []*architectureRule `yaml:"rules"`
+}
+
+type architectureRule struct {
+	Name  string   `yaml:"name"`
+	From  []string `yaml:"from"`
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+
+	from  *pathMatcher
+	allow *pathMatcher
+	deny  *pathMatcher
+}
+
+func loadArchitectureRules(path string) (*architectureRules, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := &architectureRules{}
+	if err = yaml.UnmarshalStrict(content, rules); err != nil {
+		return nil, fmt.Errorf("could not parse architecture rules from %q: %v", path, err)
+	}
+
+	for idx, rule := range rules.Rules {
+		if rule.Name == "" {
+			rule.Name = "#" + strconv.Itoa(idx+1)
+		}
+
+		if len(rule.From) == 0 {
+			return nil, fmt.Errorf("architecture rule %s in %q does not specify any 'from' patterns", rule.Name, path)
+		} else if len(rule.Allow) == 0 && len(rule.Deny) == 0 {
+			return nil, fmt.Errorf("architecture rule %s in %q specifies neither 'allow' nor 'deny' patterns", rule.Name, path)
+		}
+
+		if rule.from, err = newPathMatcher(rule.From...); err != nil {
+			return nil, err
+		}
+
+		if rule.allow, err = newPathMatcher(rule.Allow...); err != nil {
+			return nil, err
+		}
+
+		if rule.deny, err = newPathMatcher(rule.Deny...); err != nil {
+			return nil, err
+		}
+	}
+
+	return rules, nil
+}
+
+// violation returns the first rule that forbids the package at the 'from' path to import the one at
+// the 'to' path, if any.
+func (r *architectureRules) violation(from string, to string) *architectureRule {
+	for _, rule := range r.Rules {
+		if !rule.from.match(from) {
+			continue
+		}
+
+		if rule.deny.match(to) || (len(rule.Allow) > 0 && !rule.allow.match(to)) {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+// checkArchitecture reports each import, in any of the files of the given packages, that violates
+// the architecture rules as an issue of the ArchitectureLinter.
+func (p *Project) checkArchitecture(logger *logrus.Logger, rules *architectureRules, packages []*Package) error {
+	byImportPath := map[string]*Package{}
+	for _, pkg := range packages {
+		byImportPath[pkg.ImportPath] = pkg
+	}
+
+	for _, pkg := range packages {
+		dir := p.Directory(filepath.FromSlash(pkg.Path))
+
+		var fileNames []string
+		for name := range dir.Files {
+			fileNames = append(fileNames, name)
+		}
+
+		sort.Strings(fileNames)
+
+		for _, name := range fileNames {
+			file := dir.Files[name]
+
+			src, err := ioutil.ReadFile(filepath.Join(p.Path, file.Path))
+			if err != nil {
+				return err
+			}
+
+			fileSet := token.NewFileSet()
+
+			parsed, err := goparser.ParseFile(fileSet, file.Path, src, goparser.ImportsOnly)
+			if err != nil {
+				logger.WithError(err).Debugf("Could not parse the imports of %q.", file.Path)
+				continue
+			}
+
+			for _, spec := range parsed.Imports {
+				importPath, _ := strconv.Unquote(spec.Path.Value)
+
+				imported, ok := byImportPath[importPath]
+				if !ok {
+					continue
+				}
+
+				rule := rules.violation(pkg.Path, imported.Path)
+				if rule == nil {
+					continue
+				}
+
+				p.addIssue(logger, &result.Issue{
+					FromLinter: ArchitectureLinter,
+					Text:       fmt.Sprintf("package %q must not import %q (rule %s)", pkg.Path, imported.Path, rule.Name),
+					Pos:        fileSet.Position(spec.Pos()),
+				})
+			}
+		}
+	}
+
+	for _, linter := range p.linters {
+		if linter == ArchitectureLinter {
+			return nil
+		}
+	}
+
+	p.linters = append(p.linters, ArchitectureLinter)
+	sort.Strings(p.linters)
+
+	return nil
+}
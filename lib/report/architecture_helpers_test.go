@@ -0,0 +1,99 @@
+package report
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ArchitectureRules(t *testing.T) {
+	rulesFile := writeTestRules(t, `rules:
+  - name: independent-domain
+    from: ["internal/domain/**"]
+    deny: ["internal/transport/**", "internal/storage"]
+  - from: ["internal/transport/**"]
+    allow: ["internal/domain/**", "internal/transport/**", "pkg/**"]
+`)
+	defer os.Remove(rulesFile)
+
+	rules, err := loadArchitectureRules(rulesFile)
+	require.NoError(t, err)
+
+	testcases := map[string]struct {
+		from         string
+		to           string
+		expectedRule string
+	}{
+		"Denied": {
+			from:         "internal/domain",
+			to:           "internal/transport/http",
+			expectedRule: "independent-domain",
+		},
+		"DeniedFromSubPackage": {
+			from:         "internal/domain/user",
+			to:           "internal/storage",
+			expectedRule: "independent-domain",
+		},
+		"NotDenied": {
+			from: "internal/domain",
+			to:   "internal/storage/sql",
+		},
+		"Allowed": {
+			from: "internal/transport/http",
+			to:   "internal/domain/user",
+		},
+		"NotAllowed": {
+			from:         "internal/transport/http",
+			to:           "internal/storage",
+			expectedRule: "#2",
+		},
+		"Unconstrained": {
+			from: "cmd/server",
+			to:   "internal/transport/http",
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			rule := rules.violation(testcase.from, testcase.to)
+			if testcase.expectedRule == "" {
+				assert.Nil(t, rule)
+			} else {
+				require.NotNil(t, rule)
+				assert.Equal(t, testcase.expectedRule, rule.Name)
+			}
+		})
+	}
+}
+
+func Test_ArchitectureRulesInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"UnknownField":   "rules:\n  - from: [foo]\n    forbid: [bar]\n",
+		"NoFrom":         "rules:\n  - deny: [bar]\n",
+		"NoConstraint":   "rules:\n  - from: [foo]\n",
+		"InvalidPattern": "rules:\n  - from: [foo]\n    deny: ['[bar']\n",
+	} {
+		rulesFile := writeTestRules(t, content)
+		_, err := loadArchitectureRules(rulesFile)
+		assert.Error(t, err, "Should not accept rules that are %s.", name)
+		_ = os.Remove(rulesFile)
+	}
+
+	_, err := loadArchitectureRules("non-existent.yaml")
+	assert.Error(t, err)
+}
+
+func writeTestRules(t *testing.T, content string) string {
+	rulesFile, err := ioutil.TempFile("", "goality-rules")
+	require.NoError(t, err)
+
+	_, err = rulesFile.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, rulesFile.Close())
+
+	return rulesFile.Name()
+}
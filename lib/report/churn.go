@@ -0,0 +1,195 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Churn describes how much a set of files changed over the analysed window of the git history.
+type Churn struct {
+	// Commits is the number of distinct commits that changed any of the files.
+	Commits int
+	// Added and Removed are the number of lines added and removed by these commits.
+	Added   int
+	Removed int
+	// LastModified is the date of the most recent commit touching any of the files.
+	LastModified time.Time
+
+	// commits holds, for the hash of each of these commits, the number of the files that it changed.
+	// It allows Churns to be combined without counting a commit more than once.
+	commits map[string]int
+}
+
+// Lines returns the total number of lines that were changed.
+func (c *Churn) Lines() int {
+	if c == nil {
+		return 0
+	}
+
+	return c.Added + c.Removed
+}
+
+// Age returns the time elapsed since the files were last modified, relative to the given moment.
+func (c *Churn) Age(now time.Time) time.Duration {
+	if c == nil || c.LastModified.IsZero() {
+		return 0
+	}
+
+	return now.Sub(c.LastModified)
+}
+
+func (c *Churn) add(other *Churn) *Churn {
+	if other == nil {
+		return c
+	} else if c == nil {
+		churn := *other
+		return &churn
+	}
+
+	churn := &Churn{
+		Added:        c.Added + other.Added,
+		Removed:      c.Removed + other.Removed,
+		LastModified: c.LastModified,
+		commits:      map[string]int{},
+	}
+
+	for _, commits := range []map[string]int{c.commits, other.commits} {
+		for hash, count := range commits {
+			churn.commits[hash] += count
+		}
+	}
+
+	churn.Commits = len(churn.commits)
+
+	if other.LastModified.After(churn.LastModified) {
+		churn.LastModified = other.LastModified
+	}
+
+	return churn
+}
+
+// subtract removes the changes of the other Churn. The LastModified date can not be recomputed and is
+// left unchanged.
+func (c *Churn) subtract(other *Churn) *Churn {
+	if c == nil || other == nil {
+		return c
+	}
+
+	churn := &Churn{
+		Added:        c.Added - other.Added,
+		Removed:      c.Removed - other.Removed,
+		LastModified: c.LastModified,
+		commits:      map[string]int{},
+	}
+
+	// A commit remains part of the result as long as it changed any of the remaining files.
+	for hash, count := range c.commits {
+		if remaining := count - other.commits[hash]; remaining > 0 {
+			churn.commits[hash] = remaining
+		}
+	}
+
+	churn.Commits = len(churn.commits)
+
+	return churn
+}
+
+// Each commit in the 'git log' output starts with a header line made of this marker followed by the
+// commit's hash and timestamp. It is followed by one '--numstat' line per modified file.
+const churnCommitMarker = "commit:"
+
+// addChurn computes the churn of the project's files from the history of the git repository that
+// contains it. Only the commits more recent than 'since', which accepts any date format understood
+// by git, are taken into account. An empty value takes the entire history into account.
+func (p *Project) addChurn(ctx context.Context, logger *logrus.Logger, since string) error {
+	args := []string{"log", "--numstat", "--no-renames", "--relative", "--format=" + churnCommitMarker + "%H %ct"}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+
+	args = append(args, "--", ".")
+
+	logger.Debugf("Retrieving the git history of %q via 'git %s'.", p.Path, strings.Join(args, " "))
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = p.Path
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("could not retrieve the git history of %q: %v: %s", p.Path, err, strings.TrimSpace(stderr.String()))
+	}
+
+	files := map[string]*File{}
+	p.root.collectFiles(files)
+
+	return parseChurn(&stdout, files)
+}
+
+// parseChurn adds the changes listed in the given 'git log --numstat' output to the corresponding
+// files. Changes to files that are not part of the project are ignored.
+func parseChurn(r io.Reader, files map[string]*File) error {
+	var (
+		commitHash string
+		commitDate time.Time
+	)
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, churnCommitMarker):
+			header := strings.Fields(strings.TrimPrefix(line, churnCommitMarker))
+			if len(header) != 2 {
+				return fmt.Errorf("malformed commit header on git log line %d: %q", lineNumber, line)
+			}
+
+			timestamp, err := strconv.ParseInt(header[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("malformed commit timestamp on git log line %d: %v", lineNumber, err)
+			}
+
+			commitHash, commitDate = header[0], time.Unix(timestamp, 0).UTC()
+
+			continue
+		}
+
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			return fmt.Errorf("malformed git log line %d: %q", lineNumber, line)
+		}
+
+		file, ok := files[fields[2]]
+		if !ok {
+			continue
+		}
+
+		// Binary files are reported with '-' instead of line counts.
+		added, _ := strconv.Atoi(fields[0])
+		removed, _ := strconv.Atoi(fields[1])
+
+		file.Churn = file.Churn.add(&Churn{
+			Commits:      1,
+			Added:        added,
+			Removed:      removed,
+			LastModified: commitDate,
+			commits:      map[string]int{commitHash: 1},
+		})
+	}
+
+	return scanner.Err()
+}
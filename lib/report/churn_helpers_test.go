@@ -0,0 +1,66 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseChurn(t *testing.T) {
+	project := createParsedProject()
+
+	files := map[string]*File{}
+	project.root.collectFiles(files)
+
+	log := `commit:a1b2c3 1590000000
+
+3	1	file.go
+-	-	bar/image.png
+commit:d4e5f6 1580000000
+
+10	0	file.go
+2	2	bar/file.go
+5	0	unknown.go
+`
+	require.NoError(t, parseChurn(strings.NewReader(log), files))
+
+	assert.Equal(t, &Churn{
+		Commits:      2,
+		Added:        13,
+		Removed:      1,
+		LastModified: time.Unix(1590000000, 0).UTC(),
+		commits:      map[string]int{"a1b2c3": 1, "d4e5f6": 1},
+	}, project.root.Files["file.go"].Churn)
+	assert.Equal(t, &Churn{
+		Commits:      1,
+		Added:        2,
+		Removed:      2,
+		LastModified: time.Unix(1580000000, 0).UTC(),
+		commits:      map[string]int{"d4e5f6": 1},
+	}, project.root.SubDirectories["bar"].Files["file.go"].Churn)
+	assert.Nil(t, project.root.SubDirectories["foo"].SubDirectories["dir"].Files["file.go"].Churn)
+
+	// The commit that changed both files is only counted once.
+	view := project.GenerateView()
+	require.Len(t, view.SubViews, 1)
+	assert.Equal(t, &Churn{
+		Commits:      2,
+		Added:        15,
+		Removed:      3,
+		LastModified: time.Unix(1590000000, 0).UTC(),
+		commits:      map[string]int{"a1b2c3": 1, "d4e5f6": 2},
+	}, view.SubViews["./..."].Churn)
+	assert.Equal(t, 18, view.SubViews["./..."].Churn.Lines())
+
+	// Removing one of the files only drops the commits that did not change any of the others.
+	remaining := view.SubViews["./..."].Churn.subtract(project.root.Files["file.go"].Churn)
+	assert.Equal(t, 1, remaining.Commits)
+	assert.Equal(t, map[string]int{"d4e5f6": 1}, remaining.commits)
+
+	assert.Error(t, parseChurn(strings.NewReader("commit:a1b2c3 yesterday\n"), files))
+	assert.Error(t, parseChurn(strings.NewReader("commit:1590000000\n"), files))
+	assert.Error(t, parseChurn(strings.NewReader("3 1 file.go\n"), files))
+}
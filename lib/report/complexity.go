@@ -0,0 +1,348 @@
+package report
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"math"
+	"sort"
+)
+
+// FunctionMetrics holds the complexity metrics of a single function or method.
+type FunctionMetrics struct {
+	// Path is the project-relative path of the file in which the function is declared.
+	Path string
+	// Name is the function's name, prefixed with the receiver's type for methods.
+	Name string
+	Line int
+
+	// Cyclomatic is the number of linearly independent paths through the function.
+	Cyclomatic int
+	// Cognitive is an estimate of how hard the function is to understand, which penalises nested
+	// control flow more than sequential control flow.
+	Cognitive int
+	// Length is the number of lines spanned by the function, including its signature.
+	Length int
+	// Nesting is the maximum depth of nested control flow structures in the function.
+	Nesting int
+}
+
+// ComplexityMetric designates one of the metrics of FunctionMetrics.
+type ComplexityMetric uint8
+
+const (
+	ComplexityCyclomatic ComplexityMetric = iota
+	ComplexityCognitive
+	ComplexityLength
+	ComplexityNesting
+)
+
+func (m ComplexityMetric) String() string {
+	switch m {
+	case ComplexityCognitive:
+		return "cognitive"
+	case ComplexityLength:
+		return "length"
+	case ComplexityNesting:
+		return "nesting"
+	default:
+		return "cyclomatic"
+	}
+}
+
+// ParseComplexityMetric returns the ComplexityMetric corresponding to the given name.
+func ParseComplexityMetric(name string) (ComplexityMetric, bool) {
+	for _, metric := range []ComplexityMetric{ComplexityCyclomatic, ComplexityCognitive, ComplexityLength, ComplexityNesting} {
+		if metric.String() == name {
+			return metric, true
+		}
+	}
+
+	return ComplexityCyclomatic, false
+}
+
+// DefaultThreshold returns the value of the metric above which a function is considered too complex.
+// These values are in line with the defaults of the corresponding 'golangci-lint' linters.
+func (m ComplexityMetric) DefaultThreshold() int {
+	switch m {
+	case ComplexityCognitive:
+		return 30
+	case ComplexityLength:
+		return 60
+	case ComplexityNesting:
+		return 4
+	default:
+		return 30
+	}
+}
+
+func (m ComplexityMetric) value(function *FunctionMetrics) int {
+	switch m {
+	case ComplexityCognitive:
+		return function.Cognitive
+	case ComplexityLength:
+		return function.Length
+	case ComplexityNesting:
+		return function.Nesting
+	default:
+		return function.Cyclomatic
+	}
+}
+
+// ComplexityStats summarises the distribution of a ComplexityMetric over a set of functions.
+type ComplexityStats struct {
+	Count int
+	Mean  float32
+	P90   int
+	Max   int
+	// OverThreshold is the number of functions for which the metric exceeds the threshold.
+	OverThreshold int
+}
+
+// Complexity returns the distribution of the given metric over all functions in this SubView.
+func (s *SubView) Complexity(metric ComplexityMetric, threshold int) ComplexityStats {
+	stats := ComplexityStats{Count: len(s.Functions)}
+	if stats.Count == 0 {
+		return stats
+	}
+
+	values := make([]int, 0, len(s.Functions))
+
+	var total int
+
+	for _, function := range s.Functions {
+		value := metric.value(function)
+		values = append(values, value)
+		total += value
+
+		if value > threshold {
+			stats.OverThreshold++
+		}
+	}
+
+	sort.Ints(values)
+
+	// Nearest-rank percentile.
+	stats.P90 = values[int(math.Ceil(0.9*float64(len(values))))-1]
+	stats.Max = values[len(values)-1]
+	stats.Mean = float32(total) / float32(len(values))
+
+	return stats
+}
+
+type sortableFunctions []*FunctionMetrics
+
+func (s sortableFunctions) Len() int      { return len(s) }
+func (s sortableFunctions) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s sortableFunctions) Less(i, j int) bool {
+	if s[i].Path != s[j].Path {
+		return s[i].Path < s[j].Path
+	}
+
+	return s[i].Line < s[j].Line
+}
+
+// analyseFunctions computes the complexity metrics of all functions and methods declared in the Go
+// source of the file at the given path. Source that does not parse cleanly is analysed on a
+// best-effort basis.
+func analyseFunctions(path string, src []byte) []*FunctionMetrics {
+	fileSet := token.NewFileSet()
+
+	file, _ := goparser.ParseFile(fileSet, path, src, 0)
+	if file == nil {
+		return nil
+	}
+
+	var functions []*FunctionMetrics
+
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+
+		visitor := &complexityVisitor{
+			cyclomatic: 1,
+			logical:    map[*ast.BinaryExpr]struct{}{},
+		}
+		ast.Walk(visitor, funcDecl.Body)
+
+		start, end := fileSet.Position(funcDecl.Pos()), fileSet.Position(funcDecl.End())
+
+		functions = append(functions, &FunctionMetrics{
+			Path:       path,
+			Name:       functionName(funcDecl),
+			Line:       start.Line,
+			Cyclomatic: visitor.cyclomatic,
+			Cognitive:  visitor.cognitive,
+			Length:     end.Line - start.Line + 1,
+			Nesting:    visitor.maxNesting,
+		})
+	}
+
+	return functions
+}
+
+func functionName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return decl.Name.Name
+	}
+
+	receiver := decl.Recv.List[0].Type
+	if star, ok := receiver.(*ast.StarExpr); ok {
+		receiver = star.X
+	}
+
+	if ident, ok := receiver.(*ast.Ident); ok {
+		return ident.Name + "." + decl.Name.Name
+	}
+
+	return decl.Name.Name
+}
+
+// complexityVisitor computes the complexity metrics of a function's body. The cognitive complexity
+// follows the rules laid out in SonarSource's white paper on the topic, except for the increment on
+// recursion.
+type complexityVisitor struct {
+	cyclomatic int
+	cognitive  int
+	nesting    int
+	maxNesting int
+
+	// Logical expressions that have already been accounted for in the cognitive complexity.
+	logical map[*ast.BinaryExpr]struct{}
+}
+
+func (v *complexityVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.IfStmt:
+		v.cyclomatic++
+		v.cognitive += 1 + v.nesting
+		v.walkIf(n)
+
+		return nil
+	case *ast.ForStmt:
+		v.cyclomatic++
+		v.cognitive += 1 + v.nesting
+		v.walk(n.Init, n.Cond, n.Post)
+		v.walkNested(n.Body)
+
+		return nil
+	case *ast.RangeStmt:
+		v.cyclomatic++
+		v.cognitive += 1 + v.nesting
+		v.walk(n.Key, n.Value, n.X)
+		v.walkNested(n.Body)
+
+		return nil
+	case *ast.SwitchStmt:
+		v.cognitive += 1 + v.nesting
+		v.walk(n.Init, n.Tag)
+		v.walkNested(n.Body)
+
+		return nil
+	case *ast.TypeSwitchStmt:
+		v.cognitive += 1 + v.nesting
+		v.walk(n.Init, n.Assign)
+		v.walkNested(n.Body)
+
+		return nil
+	case *ast.SelectStmt:
+		v.cognitive += 1 + v.nesting
+		v.walkNested(n.Body)
+
+		return nil
+	case *ast.FuncLit:
+		v.walkNested(n.Body)
+
+		return nil
+	case *ast.CaseClause:
+		if n.List != nil {
+			v.cyclomatic++
+		}
+	case *ast.CommClause:
+		if n.Comm != nil {
+			v.cyclomatic++
+		}
+	case *ast.BranchStmt:
+		if n.Tok == token.GOTO || n.Label != nil {
+			v.cognitive++
+		}
+	case *ast.BinaryExpr:
+		if n.Op == token.LAND || n.Op == token.LOR {
+			v.cyclomatic++
+			v.countLogicalSequences(n)
+		}
+	}
+
+	return v
+}
+
+func (v *complexityVisitor) walkIf(n *ast.IfStmt) {
+	v.walk(n.Init, n.Cond)
+	v.walkNested(n.Body)
+
+	switch elseNode := n.Else.(type) {
+	case *ast.IfStmt:
+		// An 'else if' does not increase the nesting.
+		v.cyclomatic++
+		v.cognitive++
+		v.walkIf(elseNode)
+	case *ast.BlockStmt:
+		v.cognitive++
+		v.walkNested(elseNode)
+	}
+}
+
+func (v *complexityVisitor) walk(nodes ...ast.Node) {
+	for _, node := range nodes {
+		if node != nil {
+			ast.Walk(v, node)
+		}
+	}
+}
+
+func (v *complexityVisitor) walkNested(node ast.Node) {
+	v.nesting++
+	if v.nesting > v.maxNesting {
+		v.maxNesting = v.nesting
+	}
+
+	ast.Walk(v, node)
+	v.nesting--
+}
+
+// countLogicalSequences increments the cognitive complexity for each sequence of identical logical
+// operators in the expression rooted at the given node, e.g. 'a && b && c || d' counts twice.
+func (v *complexityVisitor) countLogicalSequences(n *ast.BinaryExpr) {
+	if _, ok := v.logical[n]; ok {
+		return
+	}
+
+	operators := v.logicalOperators(n)
+	for idx := range operators {
+		if idx == 0 || operators[idx] != operators[idx-1] {
+			v.cognitive++
+		}
+	}
+}
+
+func (v *complexityVisitor) logicalOperators(expr ast.Expr) []token.Token {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return v.logicalOperators(e.X)
+	case *ast.BinaryExpr:
+		if e.Op != token.LAND && e.Op != token.LOR {
+			return nil
+		}
+
+		v.logical[e] = struct{}{}
+
+		operators := v.logicalOperators(e.X)
+		operators = append(operators, e.Op)
+
+		return append(operators, v.logicalOperators(e.Y)...)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,128 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AnalyseFunctions(t *testing.T) {
+	testcases := map[string]struct {
+		source   string
+		expected *FunctionMetrics
+	}{
+		"Empty": {
+			source:   "func empty() {}",
+			expected: &FunctionMetrics{Name: "empty", Cyclomatic: 1, Length: 1},
+		},
+		"Method": {
+			source:   "func (f *foo) method() {\n}",
+			expected: &FunctionMetrics{Name: "foo.method", Cyclomatic: 1, Length: 2},
+		},
+		"IfElseChain": {
+			source: `func chain(a int) int {
+	if a == 1 {
+		return 1
+	} else if a == 2 {
+		return 2
+	} else {
+		return 3
+	}
+}`,
+			expected: &FunctionMetrics{Name: "chain", Cyclomatic: 3, Cognitive: 3, Length: 9, Nesting: 1},
+		},
+		"Switch": {
+			source: `func sw(a int) int {
+	switch a {
+	case 1, 2:
+		return 1
+	case 3:
+		return 3
+	default:
+		return 0
+	}
+}`,
+			expected: &FunctionMetrics{Name: "sw", Cyclomatic: 3, Cognitive: 1, Length: 10, Nesting: 1},
+		},
+		"LogicalSequences": {
+			source: `func logic(a, b, c, d bool) bool {
+	return a && b && (c || d)
+}`,
+			expected: &FunctionMetrics{Name: "logic", Cyclomatic: 4, Cognitive: 2, Length: 3},
+		},
+		"Nested": {
+			source: `func nested(items [][]int) {
+	for _, row := range items {
+		for _, item := range row {
+			if item > 0 {
+				go func() {
+					select {
+					case <-done:
+					default:
+					}
+				}()
+			}
+		}
+	}
+}`,
+			expected: &FunctionMetrics{Name: "nested", Cyclomatic: 5, Cognitive: 1 + 2 + 3 + 5, Length: 14, Nesting: 5},
+		},
+		"LabeledBranch": {
+			source: `func labeled(items [][]int) {
+outer:
+	for _, row := range items {
+		for range row {
+			continue outer
+		}
+	}
+}`,
+			expected: &FunctionMetrics{Name: "labeled", Cyclomatic: 3, Cognitive: 1 + 2 + 1, Length: 8, Nesting: 2},
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			functions := analyseFunctions("fake.go", []byte("package fake\n\n"+testcase.source+"\n"))
+			require.Len(t, functions, 1)
+
+			testcase.expected.Path = "fake.go"
+			testcase.expected.Line = 3
+			assert.Equal(t, testcase.expected, functions[0])
+		})
+	}
+}
+
+func Test_AnalyseFunctionsInvalidSource(t *testing.T) {
+	functions := analyseFunctions("fake.go", []byte("package fake\n\nfunc valid() {}\n\nfunc {{{\n"))
+	require.Len(t, functions, 1)
+	assert.Equal(t, "valid", functions[0].Name)
+
+	assert.Nil(t, analyseFunctions("fake.go", []byte("not Go")))
+}
+
+func Test_Complexity(t *testing.T) {
+	subView := &SubView{}
+	assert.Equal(t, ComplexityStats{}, subView.Complexity(ComplexityCyclomatic, 10))
+
+	for idx := 1; idx <= 20; idx++ {
+		subView.Functions = append(subView.Functions, &FunctionMetrics{Cyclomatic: idx, Length: 2 * idx})
+	}
+
+	assert.Equal(t, ComplexityStats{
+		Count:         20,
+		Mean:          10.5,
+		P90:           18,
+		Max:           20,
+		OverThreshold: 5,
+	}, subView.Complexity(ComplexityCyclomatic, 15))
+
+	assert.Equal(t, ComplexityStats{
+		Count:         20,
+		Mean:          21,
+		P90:           36,
+		Max:           40,
+		OverThreshold: 0,
+	}, subView.Complexity(ComplexityLength, 60))
+}
@@ -0,0 +1,230 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"go/build"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Coverage holds the statement coverage of a set of files as recorded in 'go test -coverprofile'
+// profiles.
+type Coverage struct {
+	Statements int
+	Covered    int
+}
+
+// Percentage returns the percentage of statements that are covered.
+func (c *Coverage) Percentage() float32 {
+	if c == nil || c.Statements == 0 {
+		return 0
+	}
+
+	return 100 * float32(c.Covered) / float32(c.Statements)
+}
+
+func (c *Coverage) add(other *Coverage) *Coverage {
+	if other == nil {
+		return c
+	} else if c == nil {
+		return &Coverage{Statements: other.Statements, Covered: other.Covered}
+	}
+
+	return &Coverage{Statements: c.Statements + other.Statements, Covered: c.Covered + other.Covered}
+}
+
+func (c *Coverage) subtract(other *Coverage) *Coverage {
+	if c == nil || other == nil {
+		return c
+	}
+
+	return &Coverage{Statements: c.Statements - other.Statements, Covered: c.Covered - other.Covered}
+}
+
+// coverBlock identifies a block of statements in a file. The same block can appear in multiple
+// profiles, e.g. when using '-coverpkg', in which case it is covered if it is in any of them.
+type coverBlock struct {
+	file      string
+	position  string
+	numStmts  int
+	wasCalled bool
+}
+
+// parseCoverProfile reads the blocks of a profile written by 'go test -coverprofile'. Its lines look
+// like:
+//
+//	mode: set
+//	github.com/me/project/pkg/file.go:10.32,12.3 1 1
+func parseCoverProfile(r io.Reader) ([]coverBlock, error) {
+	var blocks []coverBlock
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		// File names can contain ':' so we look for the last one.
+		sep := strings.LastIndex(line, ":")
+		if sep < 0 {
+			return nil, fmt.Errorf("malformed coverage profile line %d: %q", lineNumber, line)
+		}
+
+		fields := strings.Fields(line[sep+1:])
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("malformed coverage profile line %d: %q", lineNumber, line)
+		}
+
+		numStmts, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("malformed statement count on coverage profile line %d: %v", lineNumber, err)
+		}
+
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("malformed execution count on coverage profile line %d: %v", lineNumber, err)
+		}
+
+		blocks = append(blocks, coverBlock{
+			file:      line[:sep],
+			position:  fields[0],
+			numStmts:  numStmts,
+			wasCalled: count > 0,
+		})
+	}
+
+	return blocks, scanner.Err()
+}
+
+// addCoverProfiles maps the blocks of the given profiles onto the project's files. Profiles refer to
+// files via their import path, which is matched to the path of a file relative to the project's root
+// after removing the given import path of that root. Blocks of files that are not part of the project,
+// e.g. because they belong to another module or are excluded, are dropped. Only files that appear in
+// a profile are assigned a Coverage.
+func (p *Project) addCoverProfiles(logger *logrus.Logger, importPath string, profilePaths ...string) error {
+	merged := map[coverBlock]bool{}
+
+	for _, profilePath := range profilePaths {
+		profile, err := os.Open(profilePath)
+		if err != nil {
+			return err
+		}
+
+		blocks, err := parseCoverProfile(profile)
+		_ = profile.Close()
+
+		if err != nil {
+			return fmt.Errorf("could not parse coverage profile %q: %w", profilePath, err)
+		}
+
+		for _, block := range blocks {
+			key := block
+			key.wasCalled = false
+			merged[key] = merged[key] || block.wasCalled
+		}
+	}
+
+	files := map[string]*File{}
+	p.root.collectFiles(files)
+
+	matches := map[string]*File{}
+
+	for block, wasCalled := range merged {
+		file, ok := matches[block.file]
+		if !ok {
+			if relPath := strings.TrimPrefix(block.file, importPath+"/"); relPath != block.file {
+				file = files[relPath]
+			}
+			matches[block.file] = file
+
+			if file == nil {
+				logger.Debugf("Coverage profile entry %q does not correspond to any file of the project.", block.file)
+			}
+		}
+
+		if file == nil {
+			continue
+		}
+
+		if file.Coverage == nil {
+			file.Coverage = &Coverage{}
+		}
+
+		file.Coverage.Statements += block.numStmts
+		if wasCalled {
+			file.Coverage.Covered += block.numStmts
+		}
+	}
+
+	return nil
+}
+
+// projectImportPath returns the import path of the package rooted at the given absolute directory,
+// based on the 'go.mod' file of the enclosing module or, outside of modules, on the GOPATH.
+func projectImportPath(path string) (string, error) {
+	for dir := path; ; dir = filepath.Dir(dir) {
+		content, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			modulePath := modulePath(content)
+			if modulePath == "" {
+				return "", fmt.Errorf("could not find the module path in %q", filepath.Join(dir, "go.mod"))
+			}
+
+			relPath, err := filepath.Rel(dir, path)
+			if err != nil {
+				return "", err
+			}
+
+			if relPath == "." {
+				return modulePath, nil
+			}
+
+			return modulePath + "/" + filepath.ToSlash(relPath), nil
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		relPath, err := filepath.Rel(filepath.Join(gopath, "src"), path)
+		if err == nil && relPath != "." && !strings.HasPrefix(relPath, "..") {
+			return filepath.ToSlash(relPath), nil
+		}
+	}
+
+	return "", fmt.Errorf("could not determine the import path of %q as it is neither part of a module nor of the GOPATH", path)
+}
+
+// modulePath returns the path declared by the 'module' directive of the given 'go.mod' content.
+func modulePath(goMod []byte) string {
+	for _, line := range strings.Split(string(goMod), "\n") {
+		fields := strings.Fields(strings.SplitN(line, "//", 2)[0])
+		if len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], "\"`")
+		}
+	}
+
+	return ""
+}
+
+func (d *Directory) collectFiles(files map[string]*File) {
+	for _, file := range d.Files {
+		files[filepath.ToSlash(file.Path)] = file
+	}
+
+	for _, subDirectory := range d.SubDirectories {
+		subDirectory.collectFiles(files)
+	}
+}
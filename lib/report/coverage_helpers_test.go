@@ -0,0 +1,131 @@
+package report
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseCoverProfile(t *testing.T) {
+	testcases := map[string]struct {
+		profile        string
+		expectedBlocks []coverBlock
+		expectedErr    bool
+	}{
+		"Empty": {
+			profile: "mode: set\n",
+		},
+		"Blocks": {
+			profile: `mode: count
+example.com/project/file.go:10.32,12.3 2 0
+example.com/project/bar/file.go:3.14,5.2 1 4
+`,
+			expectedBlocks: []coverBlock{
+				{file: "example.com/project/file.go", position: "10.32,12.3", numStmts: 2},
+				{file: "example.com/project/bar/file.go", position: "3.14,5.2", numStmts: 1, wasCalled: true},
+			},
+		},
+		"MissingFields": {
+			profile:     "mode: set\nexample.com/project/file.go:10.32,12.3 2\n",
+			expectedErr: true,
+		},
+		"MissingFileName": {
+			profile:     "mode: set\n10.32,12.3 2 1\n",
+			expectedErr: true,
+		},
+		"InvalidCount": {
+			profile:     "mode: set\nexample.com/project/file.go:10.32,12.3 2 yes\n",
+			expectedErr: true,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			blocks, err := parseCoverProfile(strings.NewReader(testcase.profile))
+			if testcase.expectedErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testcase.expectedBlocks, blocks)
+			}
+		})
+	}
+}
+
+func Test_AddCoverProfiles(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	unitProfile := writeTestProfile(t, `mode: set
+example.com/project/file.go:10.32,12.3 2 1
+example.com/project/file.go:14.2,16.3 3 0
+example.com/project/bar/file.go:3.14,5.2 1 0
+example.com/other/unknown.go:1.1,2.2 5 1
+example.com/other/file.go:1.1,2.2 7 1
+example.com/project/bar/my_exclude/file.go:1.1,2.2 11 1
+example.com/project-fork/file.go:1.1,2.2 13 1
+`)
+	defer os.Remove(unitProfile)
+
+	integrationProfile := writeTestProfile(t, `mode: set
+example.com/project/file.go:10.32,12.3 2 0
+example.com/project/bar/file.go:3.14,5.2 1 1
+`)
+	defer os.Remove(integrationProfile)
+
+	project := createParsedProject()
+	require.NoError(t, project.addCoverProfiles(logger, "example.com/project", unitProfile, integrationProfile))
+
+	assert.Equal(t, &Coverage{Statements: 5, Covered: 2}, project.root.Files["file.go"].Coverage)
+	assert.Equal(t, &Coverage{Statements: 1, Covered: 1}, project.root.SubDirectories["bar"].Files["file.go"].Coverage)
+	assert.Nil(t, project.root.SubDirectories["foo"].SubDirectories["dir"].Files["file.go"].Coverage)
+
+	view := project.GenerateView()
+	require.Len(t, view.SubViews, 1)
+	rootView := view.SubViews["./..."]
+	assert.Equal(t, &Coverage{Statements: 6, Covered: 3}, rootView.Coverage)
+	assert.InDelta(t, 50, rootView.Coverage.Percentage(), 0.01)
+
+	assert.Error(t, project.addCoverProfiles(logger, "example.com/project", "non-existent.out"))
+}
+
+func Test_ProjectImportPath(t *testing.T) {
+	modulePath, err := ioutil.TempDir("", "goality-module")
+	require.NoError(t, err)
+
+	defer func() { _ = os.RemoveAll(modulePath) }()
+
+	goMod := "// The test module.\nmodule \"example.com/project\" // Quoted.\n\ngo 1.14\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(modulePath, "go.mod"), []byte(goMod), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(modulePath, "sub", "dir"), 0755))
+
+	importPath, err := projectImportPath(modulePath)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/project", importPath)
+
+	importPath, err = projectImportPath(filepath.Join(modulePath, "sub", "dir"))
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/project/sub/dir", importPath)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(modulePath, "sub", "go.mod"), []byte("go 1.14\n"), 0644))
+	_, err = projectImportPath(filepath.Join(modulePath, "sub"))
+	assert.Error(t, err, "Should not accept a 'go.mod' file without module path.")
+}
+
+func writeTestProfile(t *testing.T, content string) string {
+	profile, err := ioutil.TempFile("", "goality-coverage")
+	require.NoError(t, err)
+
+	_, err = profile.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, profile.Close())
+
+	return profile.Name()
+}
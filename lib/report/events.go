@@ -0,0 +1,61 @@
+package report
+
+// EventType identifies the kind of progress reported by an Event.
+type EventType uint8
+
+const (
+	EventTypeUnknown EventType = iota
+	// EventTypeDirectoryParsed is emitted once a directory's files have been parsed. The event's path
+	// is the directory itself.
+	EventTypeDirectoryParsed
+	// EventTypeLintQueued is emitted when a sub-tree of the project is queued for linting. The
+	// event's path is the root directory of the sub-tree.
+	EventTypeLintQueued
+	// EventTypeLinterStarted is emitted right before the linter is run. The event's path is the
+	// target passed to the linter, i.e. ending in '/...' for recursive runs.
+	EventTypeLinterStarted
+	// EventTypeLinterKilled is emitted when a linter was killed due to resource constraints after
+	// which its work is split over smaller sub-trees.
+	EventTypeLinterKilled
+	// EventTypeIssuesRegistered is emitted when the results of a successful linter run have been
+	// registered. The event's issue count indicates how many issues were found.
+	EventTypeIssuesRegistered
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventTypeDirectoryParsed:
+		return "directory-parsed"
+	case EventTypeLintQueued:
+		return "lint-queued"
+	case EventTypeLinterStarted:
+		return "linter-started"
+	case EventTypeLinterKilled:
+		return "linter-killed"
+	case EventTypeIssuesRegistered:
+		return "issues-registered"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes the progress made during an analysis.
+type Event struct {
+	Type EventType
+	// Path is relative to the root of the project being analysed.
+	Path string
+	// IssueCount is only set for events of type EventTypeIssuesRegistered.
+	IssueCount int
+}
+
+// EventHandler is called synchronously for each event emitted during an analysis. Handlers should
+// therefore return promptly.
+type EventHandler func(*Event)
+
+type eventEmitter []EventHandler
+
+func (e eventEmitter) emit(event *Event) {
+	for _, handler := range e {
+		handler(event)
+	}
+}
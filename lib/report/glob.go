@@ -0,0 +1,145 @@
+package report
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// pathMatcher matches project-relative paths against a set of glob patterns. Patterns support the
+// usual '*', '?' and '[...]' wildcards which never match a path separator, as well as '**' which
+// matches any number of path elements. Patterns that do not contain a '/' are matched against the
+// base name of a path only, all others are anchored at the root. Regular expressions can be added to
+// the same matcher and are applied as-is to the slash-separated relative path.
+type pathMatcher struct {
+	patterns []*regexp.Regexp
+}
+
+func newPathMatcher(patterns ...string) (*pathMatcher, error) {
+	matcher := &pathMatcher{}
+
+	for _, pattern := range patterns {
+		re, err := globToRegexp(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		matcher.patterns = append(matcher.patterns, re)
+	}
+
+	return matcher, nil
+}
+
+func (m *pathMatcher) addRegexps(patterns ...string) error {
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid regular expression %q: %v", pattern, err)
+		}
+
+		m.patterns = append(m.patterns, re)
+	}
+
+	return nil
+}
+
+func (m *pathMatcher) match(relPath string) bool {
+	if m == nil {
+		return false
+	}
+
+	for _, pattern := range m.patterns {
+		if pattern.MatchString(relPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	glob := strings.TrimPrefix(pattern, "./")
+
+	// A pattern containing a separator, including a leading one, is anchored at the root.
+	anchored := strings.Contains(glob, "/")
+	glob = strings.TrimPrefix(glob, "/")
+
+	if glob == "" {
+		return nil, fmt.Errorf("invalid glob pattern %q: pattern is empty", pattern)
+	}
+
+	if _, err := path.Match(strings.ReplaceAll(glob, "**", "*"), ""); err != nil {
+		return nil, fmt.Errorf("invalid glob pattern %q: %v", pattern, err)
+	}
+
+	expr := &strings.Builder{}
+	expr.WriteString("^")
+
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+
+	// A trailing '/**' matches the directory itself as well as everything below it.
+	suffix := "$"
+	if strings.HasSuffix(glob, "/**") {
+		glob = strings.TrimSuffix(glob, "/**")
+		suffix = "(?:/.*)?$"
+	}
+
+	for idx := 0; idx < len(glob); idx++ {
+		switch c := glob[idx]; c {
+		case '*':
+			if idx+1 < len(glob) && glob[idx+1] == '*' {
+				idx++
+
+				if idx+1 < len(glob) && glob[idx+1] == '/' {
+					idx++
+					expr.WriteString("(?:.*/)?")
+				} else {
+					expr.WriteString(".*")
+				}
+			} else {
+				expr.WriteString("[^/]*")
+			}
+		case '?':
+			expr.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[idx:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid glob pattern %q: unterminated character class", pattern)
+			}
+
+			class := glob[idx+1 : idx+end]
+
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+
+			expr.WriteString("[" + class + "]")
+			idx += end
+		case '\\':
+			if idx+1 < len(glob) {
+				idx++
+			}
+
+			idx += writeLiteralRune(expr, glob[idx:]) - 1
+		default:
+			idx += writeLiteralRune(expr, glob[idx:]) - 1
+		}
+	}
+
+	expr.WriteString(suffix)
+
+	return regexp.Compile(expr.String())
+}
+
+// writeLiteralRune writes the expression matching the first rune of the given text, which may span
+// several bytes, and returns its length in bytes.
+func writeLiteralRune(expr *strings.Builder, text string) int {
+	_, size := utf8.DecodeRuneInString(text)
+	expr.WriteString(regexp.QuoteMeta(text[:size]))
+
+	return size
+}
@@ -0,0 +1,128 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PathMatcher(t *testing.T) {
+	testcases := map[string]struct {
+		pattern    string
+		matches    []string
+		nonMatches []string
+		invalid    bool
+	}{
+		"BaseName": {
+			pattern:    "*.pb.go",
+			matches:    []string{"api.pb.go", "internal/api/api.pb.go"},
+			nonMatches: []string{"api.go", "internal/api.pb.go/file.go"},
+		},
+		"RootRelative": {
+			pattern:    "internal/*.go",
+			matches:    []string{"internal/file.go"},
+			nonMatches: []string{"pkg/internal/file.go", "internal/sub/file.go"},
+		},
+		"LeadingSlash": {
+			pattern:    "/internal/*.go",
+			matches:    []string{"internal/file.go"},
+			nonMatches: []string{"pkg/internal/file.go"},
+		},
+		"AnchoredBaseName": {
+			pattern:    "/file.go",
+			matches:    []string{"file.go"},
+			nonMatches: []string{"pkg/file.go"},
+		},
+		"NonASCII": {
+			pattern:    "café/**",
+			matches:    []string{"café", "café/menu.go"},
+			nonMatches: []string{"cafe/menu.go", "cafés/menu.go"},
+		},
+		"NonASCIIWildcards": {
+			pattern:    "r?sum\\é_*.go",
+			matches:    []string{"résumé_test.go", "pkg/résumé_v2.go"},
+			nonMatches: []string{"résume_test.go", "rsumé_test.go"},
+		},
+		"DoubleStarPrefix": {
+			pattern:    "**/mocks/*.go",
+			matches:    []string{"mocks/file.go", "pkg/mocks/file.go", "pkg/sub/mocks/file.go"},
+			nonMatches: []string{"pkg/mocks/sub/file.go", "pkg/mocks"},
+		},
+		"DoubleStarSuffix": {
+			pattern:    "internal/legacy/**",
+			matches:    []string{"internal/legacy", "internal/legacy/file.go", "internal/legacy/sub/file.go"},
+			nonMatches: []string{"legacy/file.go", "pkg/internal/legacy/file.go", "internal/legacyfile.go"},
+		},
+		"DoubleStarMiddle": {
+			pattern:    "pkg/**/gen_*.go",
+			matches:    []string{"pkg/gen_a.go", "pkg/a/b/gen_a.go"},
+			nonMatches: []string{"gen_a.go", "pkg/a/b/a.go"},
+		},
+		"CharacterClasses": {
+			pattern:    "file[0-9].go",
+			matches:    []string{"file1.go", "dir/file2.go"},
+			nonMatches: []string{"filea.go"},
+		},
+		"NegatedCharacterClasses": {
+			pattern:    "file[!0-9].go",
+			matches:    []string{"filea.go"},
+			nonMatches: []string{"file1.go"},
+		},
+		"SingleCharacter": {
+			pattern:    "a?c",
+			matches:    []string{"abc"},
+			nonMatches: []string{"ac", "a/c"},
+		},
+		"Escaped": {
+			pattern:    `\*.go`,
+			matches:    []string{"*.go"},
+			nonMatches: []string{"file.go"},
+		},
+		"Unterminated": {
+			pattern: "file[0-9.go",
+			invalid: true,
+		},
+		"Empty": {
+			pattern: "",
+			invalid: true,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			matcher, err := newPathMatcher(testcase.pattern)
+			if testcase.invalid {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			for _, path := range testcase.matches {
+				assert.True(t, matcher.match(path), "Pattern %q should match %q.", testcase.pattern, path)
+			}
+
+			for _, path := range testcase.nonMatches {
+				assert.False(t, matcher.match(path), "Pattern %q should not match %q.", testcase.pattern, path)
+			}
+		})
+	}
+}
+
+func Test_PathMatcherRegexps(t *testing.T) {
+	matcher, err := newPathMatcher("internal/legacy/**")
+	require.NoError(t, err)
+	require.NoError(t, matcher.addRegexps(`_mock\.go$`, `^gen/`))
+
+	for _, path := range []string{"internal/legacy", "foo_mock.go", "pkg/foo_mock.go", "gen/foo.go"} {
+		assert.True(t, matcher.match(path), "Should match %q.", path)
+	}
+
+	for _, path := range []string{"legacy", "foo.go", "pkg/gen/foo.go", "generated"} {
+		assert.False(t, matcher.match(path), "Should not match %q.", path)
+	}
+
+	assert.Error(t, matcher.addRegexps(`foo(`))
+}
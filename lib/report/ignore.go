@@ -0,0 +1,194 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// The names of the files, in order of increasing precedence, from which ignore patterns are read in
+// each directory of a project.
+var ignoreFileNames = []string{".gitignore", ".goalityignore"}
+
+type ignoreRule struct {
+	// The slash-separated directory, relative to the root of the ignoreMatcher, in which the rule was
+	// defined. The rule's pattern is relative to it.
+	base    string
+	pattern *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ignoreMatcher implements the semantics of '.gitignore' files. Rules are evaluated in the order in
+// which they were loaded and the last matching rule wins. As directories are loaded while walking
+// down the project's tree the rules of deeper ignore files take precedence over those of their
+// parents.
+type ignoreMatcher struct {
+	// The slash-separated path of the project relative to the root of the repository containing it.
+	// Paths passed to the matcher are relative to the project and need to be prefixed by it.
+	projectPrefix string
+	rules         []ignoreRule
+}
+
+// newIgnoreMatcher returns a matcher for the project at the given absolute path. If the project is
+// part of a git repository this loads the repository's '.git/info/exclude' file as well as any ignore
+// files located in the directories between the root of the repository and the project.
+func newIgnoreMatcher(projectPath string) (*ignoreMatcher, error) {
+	repoRoot := findRepositoryRoot(projectPath)
+	if repoRoot == "" {
+		return &ignoreMatcher{projectPrefix: "."}, nil
+	}
+
+	relPath, err := filepath.Rel(repoRoot, projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	matcher := &ignoreMatcher{projectPrefix: filepath.ToSlash(relPath)}
+
+	if err = matcher.loadFile(filepath.Join(repoRoot, ".git", "info", "exclude"), "."); err != nil {
+		return nil, err
+	}
+
+	// Load the ignore files of all parent directories of the project inside the repository. Those of
+	// the project's root itself are loaded when walking the project.
+	dir := "."
+	for _, element := range strings.Split(matcher.projectPrefix, "/") {
+		if element == "." {
+			break
+		}
+
+		if err = matcher.loadDirectory(filepath.Join(repoRoot, filepath.FromSlash(dir)), dir); err != nil {
+			return nil, err
+		}
+
+		dir = path.Join(dir, element)
+	}
+
+	return matcher, nil
+}
+
+func findRepositoryRoot(projectPath string) string {
+	for dir := projectPath; ; dir = filepath.Dir(dir) {
+		if info, err := os.Stat(filepath.Join(dir, ".git")); err == nil && info.IsDir() {
+			return dir
+		}
+
+		if filepath.Dir(dir) == dir {
+			return ""
+		}
+	}
+}
+
+// load reads the ignore files located in the project directory at the given absolute and relative
+// paths.
+func (m *ignoreMatcher) load(absPath string, relPath string) error {
+	if m == nil {
+		return nil
+	}
+
+	return m.loadDirectory(absPath, path.Join(m.projectPrefix, filepath.ToSlash(relPath)))
+}
+
+func (m *ignoreMatcher) loadDirectory(absPath string, base string) error {
+	for _, name := range ignoreFileNames {
+		if err := m.loadFile(filepath.Join(absPath, name), base); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *ignoreMatcher) loadFile(filePath string, base string) error {
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		rule, ok, ruleErr := parseIgnoreRule(scanner.Text(), base)
+		if ruleErr != nil {
+			return ruleErr
+		} else if ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+
+	return scanner.Err()
+}
+
+var trailingSpacesRegexp = regexp.MustCompile(`(^|[^\\])\s+$`)
+
+func parseIgnoreRule(line string, base string) (ignoreRule, bool, error) {
+	line = trailingSpacesRegexp.ReplaceAllString(strings.TrimSuffix(line, "\r"), "$1")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false, nil
+	}
+
+	rule := ignoreRule{base: base}
+
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+
+	if line == "" {
+		return ignoreRule{}, false, nil
+	}
+
+	var err error
+	if rule.pattern, err = globToRegexp(line); err != nil {
+		return ignoreRule{}, false, err
+	}
+
+	return rule, true, nil
+}
+
+// match determines whether the directory or file at the given path, relative to the project, is
+// ignored.
+func (m *ignoreMatcher) match(relPath string, isDir bool) bool {
+	if m == nil {
+		return false
+	}
+
+	fullPath := path.Join(m.projectPrefix, filepath.ToSlash(relPath))
+
+	var ignored bool
+
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		rulePath := fullPath
+		if rule.base != "." {
+			if !strings.HasPrefix(fullPath, rule.base+"/") {
+				continue
+			}
+
+			rulePath = strings.TrimPrefix(fullPath, rule.base+"/")
+		}
+
+		if rule.pattern.MatchString(rulePath) {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
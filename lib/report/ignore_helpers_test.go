@@ -0,0 +1,96 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IgnoreMatcher(t *testing.T) {
+	type ignoreFile struct {
+		base  string
+		lines []string
+	}
+
+	testcases := map[string]struct {
+		projectPrefix string
+		files         []ignoreFile
+		ignored       []string
+		notIgnored    []string
+	}{
+		"BaseName": {
+			files:      []ignoreFile{{base: ".", lines: []string{"*.gen.go", "# comment", "", "bin"}}},
+			ignored:    []string{"foo.gen.go", "pkg/foo.gen.go", "bin", "cmd/bin"},
+			notIgnored: []string{"foo.go", "# comment"},
+		},
+		"Anchored": {
+			files:      []ignoreFile{{base: ".", lines: []string{"/build", "docs/*.go"}}},
+			ignored:    []string{"build", "docs/example.go"},
+			notIgnored: []string{"pkg/build", "pkg/docs/example.go", "docs/sub/example.go"},
+		},
+		"DirectoryOnly": {
+			files:      []ignoreFile{{base: ".", lines: []string{"out/"}}},
+			ignored:    []string{"out/", "pkg/out/"},
+			notIgnored: []string{"out", "pkg/out"},
+		},
+		"Negation": {
+			files:      []ignoreFile{{base: ".", lines: []string{"*.go", "!main.go", `\!bang.go`}}},
+			ignored:    []string{"foo.go", "pkg/bar.go"},
+			notIgnored: []string{"main.go", "pkg/main.go"},
+		},
+		"Nested": {
+			files: []ignoreFile{
+				{base: ".", lines: []string{"*.pb.go"}},
+				{base: "api", lines: []string{"!keep.pb.go", "/local.go"}},
+			},
+			ignored:    []string{"foo.pb.go", "api/foo.pb.go", "pkg/keep.pb.go", "api/local.go"},
+			notIgnored: []string{"api/keep.pb.go", "api/sub/keep.pb.go", "local.go", "api/sub/local.go"},
+		},
+		"ProjectInRepository": {
+			projectPrefix: "services/api",
+			files: []ignoreFile{
+				{base: ".", lines: []string{"/services/api/tmp", "/tmp"}},
+				{base: "services", lines: []string{"*_mock.go"}},
+				{base: "services/web", lines: []string{"*.go"}},
+			},
+			ignored:    []string{"tmp", "foo_mock.go", "sub/foo_mock.go"},
+			notIgnored: []string{"foo.go", "sub/tmp"},
+		},
+		"TrailingSpaces": {
+			files:      []ignoreFile{{base: ".", lines: []string{"foo.go  ", `bar.go\ `}}},
+			ignored:    []string{"foo.go", "bar.go "},
+			notIgnored: []string{"bar.go"},
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			matcher := &ignoreMatcher{projectPrefix: "."}
+			if testcase.projectPrefix != "" {
+				matcher.projectPrefix = testcase.projectPrefix
+			}
+
+			for _, file := range testcase.files {
+				for _, line := range file.lines {
+					rule, ok, err := parseIgnoreRule(line, file.base)
+					require.NoError(t, err)
+
+					if ok {
+						matcher.rules = append(matcher.rules, rule)
+					}
+				}
+			}
+
+			for _, path := range testcase.ignored {
+				isDir := len(path) > 0 && path[len(path)-1] == '/'
+				assert.True(t, matcher.match(path, isDir), "Path %q should be ignored.", path)
+			}
+
+			for _, path := range testcase.notIgnored {
+				assert.False(t, matcher.match(path, false), "Path %q should not be ignored.", path)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package report
+
+import (
+	"go/scanner"
+	"go/token"
+	"strings"
+)
+
+// LineMetric determines which lines of a project are used as the denominator when computing the
+// rate at which issues occur.
+type LineMetric uint8
+
+const (
+	// LineMetricCode only counts lines containing code. A line holding both code and a comment is
+	// considered to be code.
+	LineMetricCode LineMetric = iota
+	// LineMetricCodeAndComments counts all lines that are not blank.
+	LineMetricCodeAndComments
+	// LineMetricTotal counts all lines, including blank ones.
+	LineMetricTotal
+)
+
+func (m LineMetric) String() string {
+	switch m {
+	case LineMetricCodeAndComments:
+		return "non-blank lines"
+	case LineMetricTotal:
+		return "lines"
+	default:
+		return "LoC"
+	}
+}
+
+// ParseLineMetric returns the LineMetric corresponding to the given name.
+func ParseLineMetric(name string) (LineMetric, bool) {
+	switch name {
+	case "code":
+		return LineMetricCode, true
+	case "code-and-comments":
+		return LineMetricCodeAndComments, true
+	case "total":
+		return LineMetricTotal, true
+	default:
+		return LineMetricCode, false
+	}
+}
+
+type lineCounts struct {
+	code    int
+	comment int
+	blank   int
+}
+
+// countLines categorises each line of the given Go source as code, comment or blank. Relying on the
+// Go scanner ensures that block comments and multi-line raw string literals are correctly accounted
+// for. Source that does not scan cleanly is counted on a best-effort basis.
+func countLines(src []byte) lineCounts {
+	lineCount := strings.Count(string(src), "\n")
+	if len(src) > 0 && src[len(src)-1] != '\n' {
+		lineCount++
+	}
+
+	fileSet := token.NewFileSet()
+	file := fileSet.AddFile("", fileSet.Base(), len(src))
+
+	var s scanner.Scanner
+	s.Init(file, src, func(token.Position, string) {}, scanner.ScanComments)
+
+	// Indexed by line number, which start at 1.
+	codeLines := make([]bool, lineCount+2)
+	commentLines := make([]bool, lineCount+2)
+
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		// Skip semicolons that were automatically inserted by the scanner.
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+
+		text := lit
+		if text == "" {
+			text = tok.String()
+		}
+
+		lines := codeLines
+		if tok == token.COMMENT {
+			lines = commentLines
+		}
+
+		startLine := file.Line(pos)
+		for line := startLine; line <= startLine+strings.Count(text, "\n") && line < len(lines); line++ {
+			lines[line] = true
+		}
+	}
+
+	counts := lineCounts{}
+
+	for line := 1; line <= lineCount; line++ {
+		switch {
+		case codeLines[line]:
+			counts.code++
+		case commentLines[line]:
+			counts.comment++
+		default:
+			counts.blank++
+		}
+	}
+
+	return counts
+}
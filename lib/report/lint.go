@@ -2,16 +2,38 @@ package report
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
+	"fmt"
 	"sort"
 	"strings"
 	"sync"
 
-	"github.com/golangci/golangci-lint/pkg/report"
 	"github.com/golangci/golangci-lint/pkg/result"
 	"github.com/sirupsen/logrus"
 )
 
+// ErrResourceLimits is returned when the linter can not complete its analysis of a directory within
+// the available memory or the configured timeout, even after splitting the work up.
+var ErrResourceLimits = errors.New("linter exceeded its resource limits")
+
+// CancellationError is returned when an analysis is interrupted via the cancellation of its context.
+// Any linter that was running at that point has been killed before the error is returned.
+type CancellationError struct {
+	// Path is the project-relative path that was being processed when the cancellation occurred.
+	Path string
+	// Err is the error of the cancelled context.
+	Err error
+}
+
+func (e *CancellationError) Error() string {
+	return fmt.Sprintf("analysis cancelled while processing %q: %v", e.Path, e.Err)
+}
+
+func (e *CancellationError) Unwrap() error {
+	return e.Err
+}
+
 type memoryMonitorFunc func(*logrus.Logger, *sync.WaitGroup, chan struct{}, chan struct{})
 
 type linter struct {
@@ -22,29 +44,57 @@ type linter struct {
 	memoryMonitory memoryMonitorFunc
 }
 
-func (l *linter) lint(project *Project) error {
-	cliArgs := append([]string{
-		"run",
-		"--issues-exit-code=0",
-		"--max-issues-per-linter=0",
-		"--max-same-issues=0",
-		"--new=false",
-		"--new-from-rev=",
-		"--out-format=json",
-	}, l.opts.toArgs()...)
-
-	todo := []*Directory{project.root}
+func (l *linter) lint(ctx context.Context, project *Project) error {
+	if err := ctx.Err(); err != nil {
+		return &CancellationError{Path: project.root.Path, Err: err}
+	}
+
+	protocol, err := l.detectProtocol(ctx, project)
+	if err != nil {
+		return l.wrapCancellation(ctx, project.root.Path, err)
+	}
+
+	cliArgs := l.opts.toArgs(protocol)
+	if protocol.skipDirsArg == "" {
+		l.logger.Debugf("golangci-lint %s can not exclude directories via its command-line. Only the retained packages will be passed to it.", protocol.version)
+	}
+
+	// Files that do not participate in the build for a given target are not analysed by the linter
+	// so it needs to run separately for each of them.
+	for _, target := range l.opts.buildTargets() {
+		l.logger.Debugf("Linting for target %s.", target)
+
+		if err = l.lintTarget(ctx, project, protocol, cliArgs, l.opts.targetEnv(target)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *linter) lintTarget(ctx context.Context, project *Project, protocol *linterProtocol, cliArgs []string, env []string) error {
+	var todo []*Directory
+
+	enqueue := func(dir *Directory) {
+		if dir.hasFiles(true) {
+			todo = append(todo, dir)
+			l.opts.eventHandlers.emit(&Event{Type: EventTypeLintQueued, Path: dir.Path})
+		}
+	}
+
+	enqueue(project.root)
+
 	for len(todo) > 0 {
 		current := todo[0]
-		todo = todo[1:]
-
-		if !current.hasFiles(true) {
-			continue
+		if err := ctx.Err(); err != nil {
+			return &CancellationError{Path: current.Path, Err: err}
 		}
 
-		interrupted, err := l.runLinter(project, cliArgs, current.Path+"/...")
+		todo = todo[1:]
+
+		interrupted, err := l.runLinter(ctx, project, protocol, cliArgs, env, current, true)
 		if err != nil {
-			return err
+			return l.wrapCancellation(ctx, current.Path, err)
 		} else if !interrupted {
 			continue
 		}
@@ -52,39 +102,60 @@ func (l *linter) lint(project *Project) error {
 		l.logger.Debugf("Spreading lint effort for '%s' over sub-directories.", current.Path)
 
 		if current.hasFiles(false) {
-			if _, err = l.runLinter(project, cliArgs, current.Path); err != nil {
-				return err
+			if interrupted, err = l.runLinter(ctx, project, protocol, cliArgs, env, current, false); err != nil {
+				return l.wrapCancellation(ctx, current.Path, err)
+			} else if interrupted {
+				return fmt.Errorf("could not lint %q: %w", current.Path, ErrResourceLimits)
 			}
 		}
 
 		for _, subDir := range current.SubDirectories {
-			todo = append(todo, subDir)
+			enqueue(subDir)
 		}
 	}
 
 	return nil
 }
 
-func (l *linter) runLinter(project *Project, cliArgs []string, path string) (bool, error) {
+func (l *linter) wrapCancellation(ctx context.Context, path string, err error) error {
+	if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return &CancellationError{Path: path, Err: err}
+	}
+
+	return err
+}
+
+func (l *linter) runLinter(ctx context.Context, project *Project, protocol *linterProtocol, cliArgs []string, env []string, dir *Directory, recursive bool) (bool, error) {
+	path := dir.Path
+	if recursive {
+		path += "/..."
+	}
+
 	l.logger.Debugf("Running linter on '%s'.", path)
+	l.opts.eventHandlers.emit(&Event{Type: EventTypeLinterStarted, Path: path})
+
+	lintArgs := append(append([]string{}, cliArgs...), protocol.lintPaths(dir, recursive)...)
 
-	output, interrupted, err := l.runManagedLinter(project, append(cliArgs, path))
+	output, interrupted, err := l.runManagedLinter(ctx, project, lintArgs, env)
 	if interrupted {
 		l.logger.Debugf("Linter run was interrupted due to resource constraints.")
+		l.opts.eventHandlers.emit(&Event{Type: EventTypeLinterKilled, Path: path})
+
 		return true, nil
 	} else if err != nil {
 		return false, err
 	}
 
-	lintOutput := &struct {
-		Issues []*result.Issue
-		Report *report.Data
-	}{}
-	if err = json.Unmarshal(output, lintOutput); err != nil {
+	lintOutput, err := protocol.decode(output)
+	if err != nil {
 		l.logger.WithError(err).Errorf("Could not parse linter output:\n%s", output)
 		return false, err
 	}
 
+	if lintOutput.Report.Error != "" {
+		l.logger.Warnf("golangci-lint reported an error while linting '%s', its issues may be incomplete: %s", path, lintOutput.Report.Error)
+	}
+
 	// If not yet registered list all enabled linters.
 	if len(project.linters) == 0 {
 		for _, linter := range lintOutput.Report.Linters {
@@ -100,20 +171,36 @@ func (l *linter) runLinter(project *Project, cliArgs []string, path string) (boo
 
 	for _, issue := range lintOutput.Issues {
 		project.addIssue(l.logger, issue)
+
+		if severity := lintOutput.Severities[issue]; severity != "" {
+			if project.reportedSeverities == nil {
+				project.reportedSeverities = map[*result.Issue]string{}
+			}
+
+			project.reportedSeverities[issue] = severity
+		}
 	}
 
+	l.opts.eventHandlers.emit(&Event{Type: EventTypeIssuesRegistered, Path: path, IssueCount: len(lintOutput.Issues)})
+
 	return false, nil
 }
 
-func (l *linter) runManagedLinter(project *Project, cliArgs []string) ([]byte, bool, error) {
-	runner := newRunner(l.logger, cliArgs)
+func (l *linter) runManagedLinter(ctx context.Context, project *Project, cliArgs []string, env []string) ([]byte, bool, error) {
+	runner := newRunner(l.logger, l.opts.linterBinary(), cliArgs)
+	runner.cmd.Env = env
+	runner.timeout = l.opts.timeout
+	runner.ignoreSignals = l.opts.noSignalHandling
+	runner.memoryMonitorFunc = l.memoryMonitory
 
 	stdout, stderr := &bytes.Buffer{}, &strings.Builder{}
 	runner.cmd.Stdout, runner.cmd.Stderr = stdout, stderr
 	runner.cmd.Dir = project.Path
 
-	interrupted, err := runner.run()
-	if err != nil && !interrupted {
+	interrupted, err := runner.run(ctx)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, false, ctxErr
+	} else if err != nil && !interrupted {
 		l.logger.WithError(err).Errorf("Linter exited with an error. Output was:\n%s Error was:\n%s", stdout.Bytes(), stderr.String())
 		return nil, false, err
 	}
@@ -0,0 +1,345 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Package holds the position of a Go package of the project within the project's internal import
+// graph. Only imports between packages of the project are taken into account.
+type Package struct {
+	ImportPath string
+	// Path is the project-relative path of the package's directory.
+	Path string
+	// Imports and ImportedBy list the import paths of the project's packages that are imported by
+	// this package and that import this package respectively.
+	Imports    []string
+	ImportedBy []string
+	// Depth is the length of the longest chain of imports starting at this package.
+	Depth int
+	// Cycle lists the import paths of the packages, including this one, that form an import cycle
+	// with this package. It is nil if the package is not part of a cycle.
+	Cycle []string
+}
+
+// Afferent returns the number of packages that depend on this package.
+func (p *Package) Afferent() int {
+	return len(p.ImportedBy)
+}
+
+// Efferent returns the number of packages on which this package depends.
+func (p *Package) Efferent() int {
+	return len(p.Imports)
+}
+
+// Instability is the ratio of efferent coupling to total coupling. It ranges from 0 for packages
+// that only have dependents to 1 for packages that only have dependencies.
+func (p *Package) Instability() float32 {
+	return instability(p.Afferent(), p.Efferent())
+}
+
+func instability(afferent int, efferent int) float32 {
+	if afferent+efferent == 0 {
+		return 0
+	}
+
+	return float32(efferent) / float32(afferent+efferent)
+}
+
+type sortablePackages []*Package
+
+func (s sortablePackages) Len() int           { return len(s) }
+func (s sortablePackages) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s sortablePackages) Less(i, j int) bool { return s[i].Path < s[j].Path }
+
+// CouplingStats describes the coupling of a set of packages, taken as a whole, with the other packages
+// of the project.
+type CouplingStats struct {
+	Packages int
+	// Afferent is the number of packages outside of the set that import a package of the set.
+	Afferent int
+	// Efferent is the number of packages outside of the set that are imported by a package of the set.
+	Efferent    int
+	Instability float32
+	// MaxDepth is the largest import depth among the packages of the set.
+	MaxDepth int
+	// InCycles is the number of packages of the set that are part of an import cycle.
+	InCycles int
+}
+
+// Coupling returns the coupling of the packages in this SubView with the rest of the project.
+func (s *SubView) Coupling() CouplingStats {
+	stats := CouplingStats{Packages: len(s.Packages)}
+
+	members := map[string]struct{}{}
+	for _, pkg := range s.Packages {
+		members[pkg.ImportPath] = struct{}{}
+	}
+
+	afferent, efferent := map[string]struct{}{}, map[string]struct{}{}
+
+	for _, pkg := range s.Packages {
+		for _, importPath := range pkg.ImportedBy {
+			if _, ok := members[importPath]; !ok {
+				afferent[importPath] = struct{}{}
+			}
+		}
+
+		for _, importPath := range pkg.Imports {
+			if _, ok := members[importPath]; !ok {
+				efferent[importPath] = struct{}{}
+			}
+		}
+
+		if pkg.Depth > stats.MaxDepth {
+			stats.MaxDepth = pkg.Depth
+		}
+
+		if len(pkg.Cycle) > 0 {
+			stats.InCycles++
+		}
+	}
+
+	stats.Afferent = len(afferent)
+	stats.Efferent = len(efferent)
+	stats.Instability = instability(stats.Afferent, stats.Efferent)
+
+	return stats
+}
+
+// goListPackage holds the fields of the output of 'go list -json' that are of interest.
+type goListPackage struct {
+	ImportPath string
+	Dir        string
+	Imports    []string
+}
+
+// listPackages lists the Go packages of the project via 'go list' and determines their position in
+// the internal import graph. Packages located in directories that are not part of the analysis are
+// left out.
+func (p *Project) listPackages(ctx context.Context, logger *logrus.Logger, env []string, tags []string) ([]*Package, error) {
+	args := []string{"list", "-e", "-json"}
+	if len(tags) > 0 {
+		args = append(args, "-tags="+strings.Join(tags, ","))
+	}
+
+	args = append(args, "./...")
+
+	logger.Debugf("Listing the packages of %q via 'go %s'.", p.Path, strings.Join(args, " "))
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = p.Path
+	cmd.Env = env
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("could not list the packages of %q: %v: %s", p.Path, err, strings.TrimSpace(stderr.String()))
+	}
+
+	listed, err := decodeGoList(&stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	var packages []*Package
+
+	for _, pkg := range listed {
+		relPath, relErr := filepath.Rel(p.Path, pkg.Dir)
+		if relErr != nil || strings.HasPrefix(relPath, "..") {
+			continue
+		}
+
+		if p.Directory(relPath) == nil {
+			logger.Debugf("Package %q is not part of the analysed files.", pkg.ImportPath)
+			continue
+		}
+
+		packages = append(packages, &Package{
+			ImportPath: pkg.ImportPath,
+			Path:       filepath.ToSlash(relPath),
+			Imports:    pkg.Imports,
+		})
+	}
+
+	computeImportGraph(packages)
+
+	return packages, nil
+}
+
+// addPackages attaches the given packages to their directories so that they are part of SubViews.
+func (p *Project) addPackages(packages []*Package) {
+	for _, pkg := range packages {
+		p.Directory(filepath.FromSlash(pkg.Path)).Package = pkg
+	}
+}
+
+func decodeGoList(r io.Reader) ([]*goListPackage, error) {
+	var packages []*goListPackage
+
+	decoder := json.NewDecoder(r)
+	for decoder.More() {
+		pkg := &goListPackage{}
+		if err := decoder.Decode(pkg); err != nil {
+			return nil, fmt.Errorf("could not decode the output of 'go list': %w", err)
+		}
+
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
+
+// computeImportGraph restricts the imports of the given packages to those among them and determines
+// their dependents, import depth and participation in import cycles.
+func computeImportGraph(packages []*Package) {
+	byImportPath := map[string]*Package{}
+	for _, pkg := range packages {
+		byImportPath[pkg.ImportPath] = pkg
+	}
+
+	for _, pkg := range packages {
+		var imports []string
+
+		for _, importPath := range pkg.Imports {
+			if imported, ok := byImportPath[importPath]; ok {
+				imports = append(imports, importPath)
+				imported.ImportedBy = append(imported.ImportedBy, pkg.ImportPath)
+			}
+		}
+
+		sort.Strings(imports)
+		pkg.Imports = imports
+	}
+
+	for _, pkg := range packages {
+		sort.Strings(pkg.ImportedBy)
+	}
+
+	graph := &importGraph{
+		packages:   byImportPath,
+		index:      map[string]int{},
+		lowLink:    map[string]int{},
+		onStack:    map[string]bool{},
+		components: map[string]int{},
+		depths:     map[int]int{},
+	}
+
+	for _, pkg := range packages {
+		if _, ok := graph.index[pkg.ImportPath]; !ok {
+			graph.connect(pkg)
+		}
+	}
+
+	for _, pkg := range packages {
+		pkg.Depth = graph.depth(graph.components[pkg.ImportPath])
+	}
+}
+
+// importGraph identifies the strongly connected components of the import graph via Tarjan's
+// algorithm. Each component with more than one package is an import cycle. The packages of a cycle
+// all share the same import depth.
+type importGraph struct {
+	packages map[string]*Package
+
+	counter int
+	index   map[string]int
+	lowLink map[string]int
+	onStack map[string]bool
+	stack   []string
+
+	// components maps each package to its component and members lists the packages of each component.
+	components map[string]int
+	members    [][]*Package
+	depths     map[int]int
+}
+
+func (g *importGraph) connect(pkg *Package) {
+	g.index[pkg.ImportPath] = g.counter
+	g.lowLink[pkg.ImportPath] = g.counter
+	g.counter++
+
+	g.stack = append(g.stack, pkg.ImportPath)
+	g.onStack[pkg.ImportPath] = true
+
+	for _, importPath := range pkg.Imports {
+		if _, ok := g.index[importPath]; !ok {
+			g.connect(g.packages[importPath])
+
+			if g.lowLink[importPath] < g.lowLink[pkg.ImportPath] {
+				g.lowLink[pkg.ImportPath] = g.lowLink[importPath]
+			}
+		} else if g.onStack[importPath] && g.index[importPath] < g.lowLink[pkg.ImportPath] {
+			g.lowLink[pkg.ImportPath] = g.index[importPath]
+		}
+	}
+
+	if g.lowLink[pkg.ImportPath] != g.index[pkg.ImportPath] {
+		return
+	}
+
+	component := len(g.members)
+	g.members = append(g.members, nil)
+
+	for {
+		last := g.stack[len(g.stack)-1]
+		g.stack = g.stack[:len(g.stack)-1]
+		g.onStack[last] = false
+
+		g.components[last] = component
+		g.members[component] = append(g.members[component], g.packages[last])
+
+		if last == pkg.ImportPath {
+			break
+		}
+	}
+
+	if len(g.members[component]) > 1 {
+		var cycle []string
+		for _, member := range g.members[component] {
+			cycle = append(cycle, member.ImportPath)
+		}
+
+		sort.Strings(cycle)
+
+		for _, member := range g.members[component] {
+			member.Cycle = cycle
+		}
+	}
+}
+
+func (g *importGraph) depth(component int) int {
+	if depth, ok := g.depths[component]; ok {
+		return depth
+	}
+
+	var depth int
+
+	for _, member := range g.members[component] {
+		for _, importPath := range member.Imports {
+			imported := g.components[importPath]
+			if imported == component {
+				continue
+			}
+
+			if importDepth := g.depth(imported) + 1; importDepth > depth {
+				depth = importDepth
+			}
+		}
+	}
+
+	g.depths[component] = depth
+
+	return depth
+}
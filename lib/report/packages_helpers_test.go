@@ -0,0 +1,86 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ComputeImportGraph(t *testing.T) {
+	var (
+		cmd    = &Package{ImportPath: "example.com/cmd", Path: "cmd", Imports: []string{"fmt", "example.com/api", "example.com/store"}}
+		api    = &Package{ImportPath: "example.com/api", Path: "api", Imports: []string{"example.com/model", "example.com/store"}}
+		store  = &Package{ImportPath: "example.com/store", Path: "store", Imports: []string{"example.com/cache"}}
+		cache  = &Package{ImportPath: "example.com/cache", Path: "cache", Imports: []string{"example.com/store", "example.com/model"}}
+		model  = &Package{ImportPath: "example.com/model", Path: "model", Imports: []string{"github.com/other/lib"}}
+		orphan = &Package{ImportPath: "example.com/orphan", Path: "orphan"}
+	)
+
+	computeImportGraph([]*Package{cmd, api, store, cache, model, orphan})
+
+	testcases := map[string]struct {
+		pkg                *Package
+		expectedImports    []string
+		expectedImportedBy []string
+		expectedDepth      int
+		expectedCycle      []string
+	}{
+		"Command": {
+			pkg:             cmd,
+			expectedImports: []string{"example.com/api", "example.com/store"},
+			expectedDepth:   3,
+		},
+		"API": {
+			pkg:                api,
+			expectedImports:    []string{"example.com/model", "example.com/store"},
+			expectedImportedBy: []string{"example.com/cmd"},
+			expectedDepth:      2,
+		},
+		"StoreInCycle": {
+			pkg:                store,
+			expectedImports:    []string{"example.com/cache"},
+			expectedImportedBy: []string{"example.com/api", "example.com/cache", "example.com/cmd"},
+			expectedDepth:      1,
+			expectedCycle:      []string{"example.com/cache", "example.com/store"},
+		},
+		"CacheInCycle": {
+			pkg:                cache,
+			expectedImports:    []string{"example.com/model", "example.com/store"},
+			expectedImportedBy: []string{"example.com/store"},
+			expectedDepth:      1,
+			expectedCycle:      []string{"example.com/cache", "example.com/store"},
+		},
+		"ExternalImportsOnly": {
+			pkg:                model,
+			expectedImportedBy: []string{"example.com/api", "example.com/cache"},
+		},
+		"Orphan": {
+			pkg: orphan,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testcase.expectedImports, testcase.pkg.Imports)
+			assert.Equal(t, testcase.expectedImportedBy, testcase.pkg.ImportedBy)
+			assert.Equal(t, testcase.expectedDepth, testcase.pkg.Depth)
+			assert.Equal(t, testcase.expectedCycle, testcase.pkg.Cycle)
+		})
+	}
+
+	subView := &SubView{Packages: []*Package{api, store, cache}}
+	assert.Equal(t, CouplingStats{
+		Packages:    3,
+		Afferent:    1,
+		Efferent:    1,
+		Instability: 0.5,
+		MaxDepth:    2,
+		InCycles:    2,
+	}, subView.Coupling())
+
+	assert.Equal(t, float32(0), model.Instability())
+	assert.Equal(t, float32(1), cmd.Instability())
+	assert.Equal(t, 2, cmd.Efferent())
+	assert.Equal(t, 2, model.Afferent())
+}
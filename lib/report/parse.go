@@ -1,14 +1,16 @@
 package report
 
 import (
-	"bytes"
+	"context"
+	"crypto/sha256"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/golangci/golangci-lint/pkg/config"
 	"github.com/golangci/golangci-lint/pkg/logutils"
@@ -26,7 +28,16 @@ var defaultExcludeDirs = []string{
 	"vendor",
 }
 
+// Parse analyses the project rooted at the given path. It is equivalent to calling ParseContext with
+// a background context.
 func Parse(logger *logrus.Logger, path string, opts ...*LintOpts) (*Project, error) {
+	return ParseContext(context.Background(), logger, path, opts...)
+}
+
+// ParseContext analyses the project rooted at the given path. Cancelling the context interrupts both
+// the parsing of the project and any running linter after which a *CancellationError wrapping the
+// context's error is returned.
+func ParseContext(ctx context.Context, logger *logrus.Logger, path string, opts ...*LintOpts) (*Project, error) {
 	if logger == nil {
 		logger = logrus.New()
 		logger.SetOutput(ioutil.Discard)
@@ -37,21 +48,76 @@ func Parse(logger *logrus.Logger, path string, opts ...*LintOpts) (*Project, err
 		return nil, err
 	}
 
-	parser := &parser{
-		logger: logger,
-		opts:   opt,
+	generated, err := newPathMatcher(opt.generatedPatterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded, err := newPathMatcher(opt.excludePatterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = excluded.addRegexps(opt.excludeRegexps...); err != nil {
+		return nil, err
 	}
-	if parser.opts == nil {
-		parser.opts = &LintOpts{}
+
+	var rules *architectureRules
+	if opt.architectureRules != "" {
+		if rules, err = loadArchitectureRules(opt.architectureRules); err != nil {
+			return nil, err
+		}
+	}
+
+	var severities *severityMapping
+	if opt.severities != "" {
+		if severities, err = loadSeverityMapping(opt.severities); err != nil {
+			return nil, err
+		}
+	}
+
+	parser := &parser{
+		logger:    logger,
+		opts:      opt,
+		generated: generated,
+		excluded:  excluded,
 	}
 
 	logger.Infof("Parsing project at path %q.", path)
 
-	project, err := parser.parse(path)
+	project, err := parser.parse(ctx, path)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(opt.coverProfiles) > 0 {
+		importPath, err := projectImportPath(project.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = project.addCoverProfiles(logger, importPath, opt.coverProfiles...); err != nil {
+			return nil, err
+		}
+	}
+
+	if opt.churn {
+		if err = project.addChurn(ctx, logger, opt.churnSince); err != nil {
+			return nil, err
+		}
+	}
+
+	var packages []*Package
+	if opt.packageMetrics || rules != nil {
+		if packages, err = project.listPackages(ctx, logger, opt.targetEnv(opt.buildTargets()[0]), opt.buildTags); err != nil {
+			return nil, err
+		}
+	}
+
+	if opt.packageMetrics {
+		project.addPackages(packages)
+	}
+
 	linter := &linter{
 		logger: logger,
 		opts:   opt,
@@ -59,17 +125,45 @@ func Parse(logger *logrus.Logger, path string, opts ...*LintOpts) (*Project, err
 
 	logger.Infof("Linting project at path %q.", path)
 
-	if err = linter.lint(project); err != nil {
+	if err = linter.lint(ctx, project); err != nil {
 		return nil, err
 	}
 
+	if rules != nil {
+		if err = project.checkArchitecture(logger, rules, packages); err != nil {
+			return nil, err
+		}
+	}
+
+	if severities != nil || len(project.reportedSeverities) > 0 {
+		project.root.assignSeverities(severities, project.reportedSeverities)
+	}
+
 	return project, nil
 }
 
 type LintOpts struct {
-	linters     []string
-	configPath  string
-	excludeDirs map[string]struct{}
+	linters           []string
+	configPath        string
+	excludeDirs       map[string]struct{}
+	excludePatterns   []string
+	excludeRegexps    []string
+	noIgnoreFiles     bool
+	targets           []Target
+	buildTags         []string
+	timeout           time.Duration
+	noSignalHandling  bool
+	eventHandlers     eventEmitter
+	binaryPath        string
+	extraArgs         []string
+	env               []string
+	generatedPatterns []string
+	coverProfiles     []string
+	churn             bool
+	churnSince        string
+	packageMetrics    bool
+	architectureRules string
+	severities        string
 }
 
 func WithLinters(linters ...string) *LintOpts {
@@ -95,6 +189,171 @@ func WithExcludeDirs(dirsToExclude ...string) *LintOpts {
 	return lintOpts
 }
 
+// WithExcludePatterns excludes all directories and Go files matching any of the given glob patterns
+// from the analysis. Patterns are relative to the project's root and support '**' to match any number
+// of directories, e.g. 'internal/legacy/**' or '**/*_mock.go'.
+func WithExcludePatterns(patterns ...string) *LintOpts {
+	return &LintOpts{
+		excludePatterns: patterns,
+		excludeDirs:     map[string]struct{}{},
+	}
+}
+
+// WithExcludeRegexps excludes all directories and Go files whose slash-separated path, relative to
+// the project's root, matches any of the given regular expressions. This mirrors the semantics of
+// the 'run.skip-files' setting of 'golangci-lint'.
+func WithExcludeRegexps(patterns ...string) *LintOpts {
+	return &LintOpts{
+		excludeRegexps: patterns,
+		excludeDirs:    map[string]struct{}{},
+	}
+}
+
+// WithTimeout limits the duration of each individual linter invocation. A linter that exceeds it is
+// killed and the corresponding work is split over smaller sub-trees of the project, the same way as
+// is done when running out of memory.
+func WithTimeout(timeout time.Duration) *LintOpts {
+	return &LintOpts{
+		timeout:     timeout,
+		excludeDirs: map[string]struct{}{},
+	}
+}
+
+// WithoutSignalHandling prevents the installation of any process-wide signal handlers while linting
+// and ensures the process is never exited on an interrupt. This is intended for programs that embed
+// the analysis and handle signals themselves: any running linter is instead killed when the context
+// passed to ParseContext is cancelled, and a *CancellationError is returned.
+func WithoutSignalHandling() *LintOpts {
+	return &LintOpts{
+		noSignalHandling: true,
+		excludeDirs:      map[string]struct{}{},
+	}
+}
+
+// WithEventHandler registers handlers that are called for each progress event emitted during the
+// analysis of a project.
+func WithEventHandler(handlers ...EventHandler) *LintOpts {
+	return &LintOpts{
+		eventHandlers: handlers,
+		excludeDirs:   map[string]struct{}{},
+	}
+}
+
+// WithBinary specifies the path of the 'golangci-lint' binary to use instead of looking it up on the
+// PATH.
+func WithBinary(binaryPath string) *LintOpts {
+	return &LintOpts{
+		binaryPath:  binaryPath,
+		excludeDirs: map[string]struct{}{},
+	}
+}
+
+// WithExtraArgs passes additional command-line arguments to each invocation of 'golangci-lint'.
+func WithExtraArgs(args ...string) *LintOpts {
+	return &LintOpts{
+		extraArgs:   args,
+		excludeDirs: map[string]struct{}{},
+	}
+}
+
+// WithEnv sets additional environment variables, in the 'KEY=value' form, for each invocation of
+// 'golangci-lint'.
+func WithEnv(env ...string) *LintOpts {
+	return &LintOpts{
+		env:         env,
+		excludeDirs: map[string]struct{}{},
+	}
+}
+
+// WithGeneratedPatterns marks all files matching any of the given glob patterns as generated, in
+// addition to those carrying the standard 'Code generated ... DO NOT EDIT.' header. Patterns are
+// relative to the project's root and support '**' to match any number of directories.
+func WithGeneratedPatterns(patterns ...string) *LintOpts {
+	return &LintOpts{
+		generatedPatterns: patterns,
+		excludeDirs:       map[string]struct{}{},
+	}
+}
+
+// WithoutIgnoreFiles disables the use of '.gitignore', '.goalityignore' and '.git/info/exclude' files
+// to skip directories and files when walking a project.
+func WithoutIgnoreFiles() *LintOpts {
+	return &LintOpts{
+		noIgnoreFiles: true,
+		excludeDirs:   map[string]struct{}{},
+	}
+}
+
+// WithTargets restricts the analysis to the files that participate in the build for at least one of
+// the given targets. The linter is run separately for each target. By default the only target is the
+// one for which the Go toolchain builds within the linter's environment.
+func WithTargets(targets ...Target) *LintOpts {
+	return &LintOpts{
+		targets:     targets,
+		excludeDirs: map[string]struct{}{},
+	}
+}
+
+// WithBuildTags sets the build tags that are used both to evaluate the build constraints of files
+// and when running 'golangci-lint'.
+func WithBuildTags(tags ...string) *LintOpts {
+	return &LintOpts{
+		buildTags:   tags,
+		excludeDirs: map[string]struct{}{},
+	}
+}
+
+// WithCoverProfiles adds the statement coverage recorded in the given 'go test -coverprofile' files
+// to the project's files. Blocks that appear in multiple profiles are covered if they are covered in
+// any of them. The profiles' import paths are mapped onto the project's files via the module path in
+// the project's 'go.mod' file or, outside of modules, via the GOPATH.
+func WithCoverProfiles(profilePaths ...string) *LintOpts {
+	return &LintOpts{
+		coverProfiles: profilePaths,
+		excludeDirs:   map[string]struct{}{},
+	}
+}
+
+// WithChurn computes the churn of the project's files from the history of the git repository that
+// contains it. Only commits more recent than 'since', which accepts any date format understood by
+// git such as '2020-01-31' or '6.months', are taken into account. An empty value takes the entire
+// history into account.
+func WithChurn(since string) *LintOpts {
+	return &LintOpts{
+		churn:       true,
+		churnSince:  since,
+		excludeDirs: map[string]struct{}{},
+	}
+}
+
+// WithPackageMetrics determines the import graph between the project's Go packages via 'go list' in
+// order to compute their coupling metrics. The graph is the one of the first target of the analysis.
+func WithPackageMetrics() *LintOpts {
+	return &LintOpts{
+		packageMetrics: true,
+		excludeDirs:    map[string]struct{}{},
+	}
+}
+
+// WithArchitectureRules checks the imports between the project's packages against the rules in the
+// given file. Violations are reported as issues of the ArchitectureLinter.
+func WithArchitectureRules(rulesPath string) *LintOpts {
+	return &LintOpts{
+		architectureRules: rulesPath,
+		excludeDirs:       map[string]struct{}{},
+	}
+}
+
+// WithSeverities assigns a severity to each issue based on the per-linter and per-text mapping in
+// the given file. Issues that are not matched by the mapping keep the severity reported by
+// golangci-lint, if any, or otherwise get the mapping's default severity.
+func WithSeverities(mappingPath string) *LintOpts {
+	return &LintOpts{
+		severities:  mappingPath,
+		excludeDirs: map[string]struct{}{},
+	}
+}
+
 func (o *LintOpts) mergeLintOpts(optsToMerge *LintOpts) error {
 	if o.configPath != "" && optsToMerge.configPath != "" {
 		return fmt.Errorf("conflicting options: multiple configuration files were specified: '%s' and '%s'", o.configPath, optsToMerge.configPath)
@@ -102,6 +361,55 @@ func (o *LintOpts) mergeLintOpts(optsToMerge *LintOpts) error {
 		o.configPath = optsToMerge.configPath
 	}
 
+	if o.timeout != 0 && optsToMerge.timeout != 0 && o.timeout != optsToMerge.timeout {
+		return fmt.Errorf("conflicting options: multiple timeouts were specified: '%s' and '%s'", o.timeout, optsToMerge.timeout)
+	} else if optsToMerge.timeout != 0 {
+		o.timeout = optsToMerge.timeout
+	}
+
+	if o.churnSince != "" && optsToMerge.churnSince != "" && o.churnSince != optsToMerge.churnSince {
+		return fmt.Errorf("conflicting options: multiple churn windows were specified: '%s' and '%s'", o.churnSince, optsToMerge.churnSince)
+	} else if optsToMerge.churnSince != "" {
+		o.churnSince = optsToMerge.churnSince
+	}
+
+	if o.architectureRules != "" && optsToMerge.architectureRules != "" && o.architectureRules != optsToMerge.architectureRules {
+		return fmt.Errorf("conflicting options: multiple architecture rules files were specified: '%s' and '%s'", o.architectureRules, optsToMerge.architectureRules)
+	} else if optsToMerge.architectureRules != "" {
+		o.architectureRules = optsToMerge.architectureRules
+	}
+
+	if o.severities != "" && optsToMerge.severities != "" && o.severities != optsToMerge.severities {
+		return fmt.Errorf("conflicting options: multiple severity mappings were specified: '%s' and '%s'", o.severities, optsToMerge.severities)
+	} else if optsToMerge.severities != "" {
+		o.severities = optsToMerge.severities
+	}
+
+	if o.binaryPath != "" && optsToMerge.binaryPath != "" && o.binaryPath != optsToMerge.binaryPath {
+		return fmt.Errorf("conflicting options: multiple linter binaries were specified: '%s' and '%s'", o.binaryPath, optsToMerge.binaryPath)
+	} else if optsToMerge.binaryPath != "" {
+		o.binaryPath = optsToMerge.binaryPath
+	}
+
+	o.extraArgs = append(o.extraArgs, optsToMerge.extraArgs...)
+	o.env = append(o.env, optsToMerge.env...)
+	o.generatedPatterns = append(o.generatedPatterns, optsToMerge.generatedPatterns...)
+	o.excludePatterns = append(o.excludePatterns, optsToMerge.excludePatterns...)
+	o.excludeRegexps = append(o.excludeRegexps, optsToMerge.excludeRegexps...)
+	o.noSignalHandling = o.noSignalHandling || optsToMerge.noSignalHandling
+	o.noIgnoreFiles = o.noIgnoreFiles || optsToMerge.noIgnoreFiles
+	o.buildTags = append(o.buildTags, optsToMerge.buildTags...)
+	o.coverProfiles = append(o.coverProfiles, optsToMerge.coverProfiles...)
+	o.churn = o.churn || optsToMerge.churn
+	o.packageMetrics = o.packageMetrics || optsToMerge.packageMetrics
+
+	for _, target := range optsToMerge.targets {
+		if !o.hasTarget(target) {
+			o.targets = append(o.targets, target)
+		}
+	}
+	o.eventHandlers = append(o.eventHandlers, optsToMerge.eventHandlers...)
+
 	var (
 		lastLinter string
 		newLinters []string
@@ -126,6 +434,40 @@ func (o *LintOpts) mergeLintOpts(optsToMerge *LintOpts) error {
 	return nil
 }
 
+func (o *LintOpts) hasTarget(target Target) bool {
+	for _, existing := range o.targets {
+		if existing == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+// buildTargets returns the targets for which the project should be analysed.
+func (o *LintOpts) buildTargets() []Target {
+	if len(o.targets) > 0 {
+		return o.targets
+	}
+
+	return []Target{defaultTarget(o.targetEnv(Target{}))}
+}
+
+// targetEnv returns the environment in which the Go toolchain builds for the given target. The
+// environment is inherited when no target is specified.
+func (o *LintOpts) targetEnv(target Target) []string {
+	env := o.linterEnv()
+	if env == nil {
+		env = os.Environ()
+	}
+
+	if target == (Target{}) {
+		return env
+	}
+
+	return append(env, target.env()...)
+}
+
 func aggregateLintOpts(opts ...*LintOpts) (*LintOpts, error) {
 	accumulator := &LintOpts{excludeDirs: map[string]struct{}{}}
 	for idx := range defaultExcludeDirs {
@@ -153,13 +495,15 @@ func aggregateLintOpts(opts ...*LintOpts) (*LintOpts, error) {
 		for _, excludeDir := range parsedConfig.Run.SkipDirs {
 			accumulator.excludeDirs[excludeDir] = struct{}{}
 		}
+
+		accumulator.excludeRegexps = append(accumulator.excludeRegexps, parsedConfig.Run.SkipFiles...)
 	}
 
 	return accumulator, nil
 }
 
-func (o *LintOpts) toArgs() []string {
-	args := []string{}
+func (o *LintOpts) toArgs(protocol *linterProtocol) []string {
+	args := protocol.baseArgs()
 	if o.configPath == "" {
 		args = append(args, "--no-config")
 	} else {
@@ -167,68 +511,83 @@ func (o *LintOpts) toArgs() []string {
 	}
 
 	if len(o.linters) > 0 {
-		args = append(args, "--disable-all", "--enable="+strings.Join(o.linters, ","))
+		args = append(args, protocol.disableAllArg, "--enable="+strings.Join(o.linters, ","))
 	}
 
-	if len(o.excludeDirs) > 0 {
+	if len(o.buildTags) > 0 {
+		args = append(args, "--build-tags="+strings.Join(o.buildTags, ","))
+	}
+
+	if len(o.excludeDirs) > 0 && protocol.skipDirsArg != "" {
 		var excludeList []string
 		for excludeDir := range o.excludeDirs {
 			excludeList = append(excludeList, excludeDir)
 		}
 
 		sort.Strings(excludeList)
-		args = append(args, "--skip-dirs="+strings.Join(excludeList, ","))
+		args = append(args, protocol.skipDirsArg+"="+strings.Join(excludeList, ","))
 	}
 
-	return args
+	return append(args, o.extraArgs...)
 }
 
 type parser struct {
 	logger *logrus.Logger
 	opts   *LintOpts
-}
-
-func (p *parser) parse(path string) (*Project, error) {
-	if !filepath.IsAbs(path) {
-		cwd, err := os.Getwd()
-		if err != nil {
-			p.logger.WithError(err).Error("Could not determine the current working directory.")
-			return nil, err
-		}
 
-		path = filepath.Join(cwd, path)
-	}
+	// The absolute path of the project being parsed. All paths handled by the parser are relative
+	// to it so that we never need to rely on the process' working directory.
+	projectPath string
+	// Files matching these patterns are considered to be generated.
+	generated *pathMatcher
+	// Directories and files matching these patterns are skipped.
+	excluded *pathMatcher
+	// Directories and files ignored by the project's ignore files are skipped.
+	ignores *ignoreMatcher
+	// The relative paths of all directories and files that were skipped.
+	excludedPaths map[string]struct{}
+	// Only files participating in the build for at least one of these are taken into account.
+	targets []Target
+}
 
-	cwd, err := os.Getwd()
+func (p *parser) parse(ctx context.Context, path string) (*Project, error) {
+	path, err := filepath.Abs(path)
 	if err != nil {
-		p.logger.WithError(err).Errorf("Could not determine the current working directory.")
+		p.logger.WithError(err).Error("Could not determine the absolute path of the project.")
 		return nil, err
 	}
 
-	if err = os.Chdir(path); err != nil {
-		p.logger.WithError(err).Errorf("Could not move to the project's root directory.")
-		return nil, err
-	}
+	p.projectPath = path
+	p.excludedPaths = map[string]struct{}{}
+	p.targets = p.opts.buildTargets()
 
-	defer func() {
-		if moveErr := os.Chdir(cwd); moveErr != nil {
-			p.logger.WithError(err).Errorf("Failed to move back to the original working directory %q.", cwd)
+	p.ignores = nil
+	if !p.opts.noIgnoreFiles {
+		if p.ignores, err = newIgnoreMatcher(path); err != nil {
+			p.logger.WithError(err).Error("Could not load the ignore files of the project's repository.")
+			return nil, err
 		}
-	}()
+	}
 
-	root, err := p.parseDirectory(".")
+	root, err := p.parseDirectory(ctx, ".")
 	if err != nil {
 		return nil, err
 	}
 
 	return &Project{
-		Path: path,
-		root: root,
+		Path:     path,
+		root:     root,
+		excluded: p.excludedPaths,
+		targets:  p.targets,
 	}, nil
 }
 
-func (p *parser) parseDirectory(path string) (*Directory, error) {
-	dir, err := os.Open(path)
+func (p *parser) parseDirectory(ctx context.Context, path string) (*Directory, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, &CancellationError{Path: path, Err: err}
+	}
+
+	dir, err := os.Open(filepath.Join(p.projectPath, path))
 	if err != nil {
 		p.logger.WithError(err).Errorf("Could not open project directory %q.", path)
 		return nil, err
@@ -242,6 +601,11 @@ func (p *parser) parseDirectory(path string) (*Directory, error) {
 		return nil, err
 	}
 
+	if err = p.ignores.load(filepath.Join(p.projectPath, path), path); err != nil {
+		p.logger.WithError(err).Errorf("Could not load the ignore files of project directory %q.", path)
+		return nil, err
+	}
+
 	directory := &Directory{
 		Path:           path,
 		SubDirectories: map[string]*Directory{},
@@ -250,85 +614,116 @@ func (p *parser) parseDirectory(path string) (*Directory, error) {
 
 	for _, dirContent := range dirContents {
 		if dirContent.IsDir() {
-			if _, ok := p.opts.excludeDirs[dirContent.Name()]; ok {
+			// Version control metadata is never part of a project's content.
+			if dirContent.Name() == ".git" && p.ignores != nil {
 				continue
 			}
 
-			subDir, dirErr := p.parseDirectory(filepath.Join(path, dirContent.Name()))
+			if p.isExcluded(filepath.Join(path, dirContent.Name()), true) {
+				continue
+			}
+
+			subDir, dirErr := p.parseDirectory(ctx, filepath.Join(path, dirContent.Name()))
 			if dirErr != nil {
 				return nil, dirErr
 			}
 
 			directory.SubDirectories[dirContent.Name()] = subDir
 		} else if strings.HasSuffix(dirContent.Name(), ".go") {
+			if p.isExcluded(filepath.Join(path, dirContent.Name()), false) {
+				continue
+			}
+
 			file, fileErr := p.parseFile(filepath.Join(path, dirContent.Name()))
 			if fileErr != nil {
 				return nil, fileErr
+			} else if file == nil {
+				continue
 			}
+
 			directory.Files[dirContent.Name()] = file
 		}
 	}
 
+	p.opts.eventHandlers.emit(&Event{Type: EventTypeDirectoryParsed, Path: path})
+
 	return directory, nil
 }
 
+// isExcluded determines whether the directory or file at the given relative path should be skipped
+// and, if so, records it as such.
+func (p *parser) isExcluded(path string, isDir bool) bool {
+	var excluded bool
+
+	if isDir {
+		_, excluded = p.opts.excludeDirs[filepath.Base(path)]
+	}
+
+	if !excluded && !p.excluded.match(filepath.ToSlash(path)) && !p.ignores.match(path, isDir) {
+		return false
+	}
+
+	p.logger.Debugf("Excluding %q from the analysis.", path)
+	p.excludedPaths[filepath.ToSlash(path)] = struct{}{}
+
+	return true
+}
+
+// parseFile returns the information for the Go file at the given relative path or nil if the file
+// does not participate in the build for any of the targets.
 func (p *parser) parseFile(path string) (*File, error) {
-	file, err := os.Open(path)
+	content, err := ioutil.ReadFile(filepath.Join(p.projectPath, path))
 	if err != nil {
-		p.logger.WithError(err).Errorf("Failed to open project file %q.", path)
+		p.logger.WithError(err).Errorf("Failed to read project file %q.", path)
 		return nil, err
 	}
 
-	defer func() { _ = file.Close() }()
+	var targets []Target
+	for _, target := range p.targets {
+		if target.matchFile(filepath.Base(path), content, p.opts.buildTags) {
+			targets = append(targets, target)
+		}
+	}
 
-	locCount, err := locCounter(file)
-	if err != nil {
-		p.logger.WithError(err).Errorf("Failed to count lines of code in project file %q.", path)
-		return nil, err
+	if len(targets) == 0 {
+		p.logger.Debugf("Skipping %q as it is not part of the build for any target.", path)
+		return nil, nil
 	}
 
+	lines := countLines(content)
+	directives, suppressions := countSuppressions(content)
+
 	return &File{
-		Path:      path,
-		LineCount: locCount,
-		Issues:    map[string][]*result.Issue{},
+		Path:                  path,
+		LineCount:             lines.code,
+		CommentLineCount:      lines.comment,
+		BlankLineCount:        lines.blank,
+		Issues:                map[string][]*result.Issue{},
+		IsTest:                strings.HasSuffix(path, "_test.go"),
+		IsGenerated:           isGenerated(content) || p.generated.match(filepath.ToSlash(path)),
+		Targets:               targets,
+		SuppressionDirectives: directives,
+		Suppressions:          suppressions,
+		Functions:             analyseFunctions(path, content),
+		Hash:                  fmt.Sprintf("%x", sha256.Sum256(content)),
 	}, nil
 }
 
-var locCounterBufferSize = 32 * 1024
-
-func locCounter(r io.Reader) (int, error) {
-	buffer := make([]byte, locCounterBufferSize)
-
-	var (
-		line  string
-		count int
-	)
-
-	for {
-		n, err := r.Read(buffer)
-		if err != nil && err != io.EOF {
-			return count, err
-		}
-
-		var idx int
-		for idx < n {
-			newIdx := bytes.IndexByte(buffer[idx:], '\n')
-			if newIdx < 0 {
-				line = strings.TrimSpace(string(buffer[idx:]))
-				break
-			}
+var generatedHeaderRegexp = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
 
-			line += strings.TrimSpace(string(buffer[idx : idx+newIdx]))
-			if line != "" && !strings.HasPrefix(line, "//") {
-				count++
-			}
-
-			line = ""
-			idx += newIdx + 1
+// isGenerated detects the standard header of generated Go files as described at
+// https://golang.org/s/generatedcode. It must appear before the package clause.
+func isGenerated(src []byte) bool {
+	for _, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if generatedHeaderRegexp.MatchString(line) {
+			return true
 		}
 
-		if err == io.EOF {
-			return count, nil
+		if strings.HasPrefix(line, "package ") {
+			return false
 		}
 	}
+
+	return false
 }
@@ -3,8 +3,8 @@ package report
 import (
 	"os"
 	"path/filepath"
-	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,6 +22,31 @@ func Test_AggregateLintOpts(t *testing.T) {
 		lintOptsE = WithConfig("bar.yaml")
 		lintOptsF = WithExcludeDirs("vendor")
 		lintOptsG = WithExcludeDirs("mocks", "vendor")
+		lintOptsH = WithTimeout(time.Minute)
+		lintOptsI = WithTimeout(time.Second)
+		lintOptsJ = WithoutSignalHandling()
+		lintOptsK = WithBinary("/opt/bin/golangci-lint")
+		lintOptsL = WithBinary("/usr/bin/golangci-lint")
+		lintOptsM = WithExtraArgs("--build-tags=integration")
+		lintOptsN = WithEnv("GOFLAGS=-mod=vendor")
+		lintOptsO = WithGeneratedPatterns("*.pb.go")
+		lintOptsP = WithGeneratedPatterns("**/mocks/**")
+		lintOptsQ = WithExcludePatterns("internal/legacy/**")
+		lintOptsR = WithExcludeRegexps(`_mock\.go$`)
+		lintOptsS = WithTargets(Target{GOOS: "linux", GOARCH: "amd64"}, Target{GOOS: "windows", GOARCH: "amd64"})
+		lintOptsT = WithTargets(Target{GOOS: "windows", GOARCH: "amd64"})
+		lintOptsU = WithBuildTags("integration")
+		lintOptsV = WithCoverProfiles("unit.out")
+		lintOptsW = WithCoverProfiles("integration.out")
+		lintOptsX = WithChurn("6.months")
+		lintOptsY = WithChurn("")
+		lintOptsZ = WithChurn("2020-01-31")
+
+		lintOptsArchA = WithArchitectureRules("layers.yaml")
+		lintOptsArchB = WithArchitectureRules("other.yaml")
+
+		lintOptsSevA = WithSeverities("severities.yaml")
+		lintOptsSevB = WithSeverities("other.yaml")
 	)
 
 	testcases := map[string]struct {
@@ -66,7 +91,83 @@ func Test_AggregateLintOpts(t *testing.T) {
 		"OneConfig": {
 			lintOpts: []*LintOpts{lintOptsA, lintOptsD},
 			expectedValue: &LintOpts{
-				configPath: lintOptsD.configPath,
+				configPath:     lintOptsD.configPath,
+				excludeRegexps: []string{`_gen\.go$`},
+				excludeDirs: map[string]struct{}{
+					"builtin":     {},
+					"examples":    {},
+					"Godeps":      {},
+					"my_exclude":  {},
+					"testdata":    {},
+					"third_party": {},
+					"vendor":      {},
+				},
+			},
+		},
+		"Timeout": {
+			lintOpts: []*LintOpts{lintOptsA, lintOptsH, lintOptsH},
+			expectedValue: &LintOpts{
+				timeout: time.Minute,
+				excludeDirs: map[string]struct{}{
+					"builtin":     {},
+					"examples":    {},
+					"Godeps":      {},
+					"testdata":    {},
+					"third_party": {},
+					"vendor":      {},
+				},
+			},
+		},
+		"NoSignalHandling": {
+			lintOpts: []*LintOpts{lintOptsJ, lintOptsA},
+			expectedValue: &LintOpts{
+				noSignalHandling: true,
+				excludeDirs: map[string]struct{}{
+					"builtin":     {},
+					"examples":    {},
+					"Godeps":      {},
+					"testdata":    {},
+					"third_party": {},
+					"vendor":      {},
+				},
+			},
+		},
+		"LinterInvocation": {
+			lintOpts: []*LintOpts{lintOptsK, lintOptsM, lintOptsN, lintOptsK},
+			expectedValue: &LintOpts{
+				binaryPath: "/opt/bin/golangci-lint",
+				extraArgs:  []string{"--build-tags=integration"},
+				env:        []string{"GOFLAGS=-mod=vendor"},
+				excludeDirs: map[string]struct{}{
+					"builtin":     {},
+					"examples":    {},
+					"Godeps":      {},
+					"testdata":    {},
+					"third_party": {},
+					"vendor":      {},
+				},
+			},
+		},
+		"GeneratedPatterns": {
+			lintOpts: []*LintOpts{lintOptsO, lintOptsA, lintOptsP},
+			expectedValue: &LintOpts{
+				generatedPatterns: []string{"*.pb.go", "**/mocks/**"},
+				excludeDirs: map[string]struct{}{
+					"builtin":     {},
+					"examples":    {},
+					"Godeps":      {},
+					"testdata":    {},
+					"third_party": {},
+					"vendor":      {},
+				},
+			},
+		},
+		"ExcludePatterns": {
+			lintOpts: []*LintOpts{lintOptsQ, lintOptsR, lintOptsD},
+			expectedValue: &LintOpts{
+				configPath:      lintOptsD.configPath,
+				excludePatterns: []string{"internal/legacy/**"},
+				excludeRegexps:  []string{`_mock\.go$`, `_gen\.go$`},
 				excludeDirs: map[string]struct{}{
 					"builtin":     {},
 					"examples":    {},
@@ -78,6 +179,70 @@ func Test_AggregateLintOpts(t *testing.T) {
 				},
 			},
 		},
+		"Targets": {
+			lintOpts: []*LintOpts{lintOptsS, lintOptsT, lintOptsU},
+			expectedValue: &LintOpts{
+				targets:   []Target{{GOOS: "linux", GOARCH: "amd64"}, {GOOS: "windows", GOARCH: "amd64"}},
+				buildTags: []string{"integration"},
+				excludeDirs: map[string]struct{}{
+					"builtin":     {},
+					"examples":    {},
+					"Godeps":      {},
+					"testdata":    {},
+					"third_party": {},
+					"vendor":      {},
+				},
+			},
+		},
+		"CoverProfiles": {
+			lintOpts: []*LintOpts{lintOptsV, lintOptsW},
+			expectedValue: &LintOpts{
+				coverProfiles: []string{"unit.out", "integration.out"},
+				excludeDirs: map[string]struct{}{
+					"builtin":     {},
+					"examples":    {},
+					"Godeps":      {},
+					"testdata":    {},
+					"third_party": {},
+					"vendor":      {},
+				},
+			},
+		},
+		"Churn": {
+			lintOpts: []*LintOpts{lintOptsX, lintOptsY},
+			expectedValue: &LintOpts{
+				churn:      true,
+				churnSince: "6.months",
+				excludeDirs: map[string]struct{}{
+					"builtin":     {},
+					"examples":    {},
+					"Godeps":      {},
+					"testdata":    {},
+					"third_party": {},
+					"vendor":      {},
+				},
+			},
+		},
+		"TwoChurnWindows": {
+			lintOpts:    []*LintOpts{lintOptsX, lintOptsZ},
+			expectedErr: true,
+		},
+		"TwoArchitectureRules": {
+			lintOpts:    []*LintOpts{lintOptsArchA, lintOptsArchB},
+			expectedErr: true,
+		},
+		"TwoSeverityMappings": {
+			lintOpts:    []*LintOpts{lintOptsSevA, lintOptsSevB},
+			expectedErr: true,
+		},
+		"TwoBinaries": {
+			lintOpts:    []*LintOpts{lintOptsK, lintOptsL},
+			expectedErr: true,
+		},
+		"TwoTimeouts": {
+			lintOpts:    []*LintOpts{lintOptsH, lintOptsI},
+			expectedErr: true,
+		},
 		"TwoConfigs": {
 			lintOpts:    []*LintOpts{lintOptsA, lintOptsD, lintOptsE},
 			expectedErr: true,
@@ -85,8 +250,9 @@ func Test_AggregateLintOpts(t *testing.T) {
 		"MultiOptions": {
 			lintOpts: []*LintOpts{lintOptsA, lintOptsB, lintOptsC, lintOptsD, lintOptsF, lintOptsG},
 			expectedValue: &LintOpts{
-				linters:    []string{"mylinter", "mystaticanalysis"},
-				configPath: lintOptsD.configPath,
+				linters:        []string{"mylinter", "mystaticanalysis"},
+				configPath:     lintOptsD.configPath,
+				excludeRegexps: []string{`_gen\.go$`},
 				excludeDirs: map[string]struct{}{
 					"builtin":     {},
 					"examples":    {},
@@ -118,62 +284,197 @@ func Test_AggregateLintOpts(t *testing.T) {
 }
 
 func Test_LintOptsToArgs(t *testing.T) {
+	legacyProtocol, err := newLinterProtocol(linterVersion{major: 1, minor: 24})
+	require.NoError(t, err)
+
+	modernProtocol, err := newLinterProtocol(linterVersion{major: 2, minor: 1})
+	require.NoError(t, err)
+
 	testcases := map[string]struct {
 		lintOpts *LintOpts
+		protocol *linterProtocol
 		expected []string
 	}{
 		"NoOpts": {
 			lintOpts: &LintOpts{},
+			protocol: legacyProtocol,
 			expected: []string{"--no-config"},
 		},
 		"ConfigOnly": {
 			lintOpts: &LintOpts{configPath: "bar.yaml"},
+			protocol: legacyProtocol,
 			expected: []string{"--config=bar.yaml"},
 		},
 		"LintersOnly": {
 			lintOpts: &LintOpts{linters: []string{"mylinter", "mystaticanalysis"}},
+			protocol: legacyProtocol,
 			expected: []string{"--no-config", "--disable-all", "--enable=mylinter,mystaticanalysis"},
 		},
 		"ExcludePathsOnly": {
 			lintOpts: &LintOpts{excludeDirs: map[string]struct{}{"mocks": {}, "vendor": {}}},
+			protocol: legacyProtocol,
 			expected: []string{"--no-config", "--skip-dirs=mocks,vendor"},
 		},
+		"BuildTagsOnly": {
+			lintOpts: &LintOpts{buildTags: []string{"integration", "e2e"}},
+			protocol: legacyProtocol,
+			expected: []string{"--no-config", "--build-tags=integration,e2e"},
+		},
+		"ExtraArgsOnly": {
+			lintOpts: &LintOpts{extraArgs: []string{"--build-tags=integration", "--timeout=5m"}},
+			protocol: legacyProtocol,
+			expected: []string{"--no-config", "--build-tags=integration", "--timeout=5m"},
+		},
 		"MultiOptions": {
 			lintOpts: &LintOpts{
 				linters:     []string{"mystaticanalysis"},
 				configPath:  "foo.yaml",
 				excludeDirs: map[string]struct{}{"vendor": {}},
 			},
+			protocol: legacyProtocol,
 			expected: []string{"--config=foo.yaml", "--disable-all", "--enable=mystaticanalysis", "--skip-dirs=vendor"},
 		},
+		"MultiOptionsModern": {
+			lintOpts: &LintOpts{
+				linters:     []string{"mystaticanalysis"},
+				configPath:  "foo.yaml",
+				excludeDirs: map[string]struct{}{"vendor": {}},
+			},
+			protocol: modernProtocol,
+			expected: []string{"--config=foo.yaml", "--default=none", "--enable=mystaticanalysis"},
+		},
 	}
 
 	for name := range testcases {
 		testcase := testcases[name]
 		t.Run(name, func(t *testing.T) {
-			cliArgs := testcase.lintOpts.toArgs()
-			assert.Equal(t, testcase.expected, cliArgs)
+			cliArgs := testcase.lintOpts.toArgs(testcase.protocol)
+			baseArgs := testcase.protocol.baseArgs()
+			require.True(t, len(cliArgs) >= len(baseArgs))
+			assert.Equal(t, baseArgs, cliArgs[:len(baseArgs)])
+			assert.Equal(t, testcase.expected, cliArgs[len(baseArgs):])
 		})
 	}
 }
 
-func Test_LoCCounter(t *testing.T) {
-	const fakeFile = `// Test file
+func Test_CountLines(t *testing.T) {
+	testcases := map[string]struct {
+		source   string
+		expected lineCounts
+	}{
+		"Empty": {},
+		"Simple": {
+			source: `// Test file
 
 package fake
 
 // Super cool main function.
 func main() {
 	/* Guess we're not doing anything */
-	os.Exit(1)
+	os.Exit(1) // Bye.
+}
+`,
+			expected: lineCounts{code: 4, comment: 3, blank: 2},
+		},
+		"BlockComments": {
+			source: `package fake
+
+/*
+Package fake does nothing.
+
+Really nothing.
+*/
+var a = /* inline */ 1
+`,
+			expected: lineCounts{code: 2, comment: 5, blank: 1},
+		},
+		"RawStrings": {
+			source:   "package fake\n\nconst text = `\n// Not a comment.\n\n/* Neither is this. */\n`\n",
+			expected: lineCounts{code: 6, blank: 1},
+		},
+		"NoTrailingNewline": {
+			source:   "package fake\n\nvar a = 1",
+			expected: lineCounts{code: 2, blank: 1},
+		},
+		"InvalidSource": {
+			source:   "package fake\n\nfunc {{{ 'unterminated\n",
+			expected: lineCounts{code: 2, blank: 1},
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testcase.expected, countLines([]byte(testcase.source)))
+		})
+	}
 }
-`
 
-	locCounterBufferSize = 32
+func Test_IsGenerated(t *testing.T) {
+	testcases := map[string]struct {
+		source   string
+		expected bool
+	}{
+		"Regular": {
+			source: "// Package fake does nothing.\npackage fake\n",
+		},
+		"Generated": {
+			source:   "// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: fake.proto\n\npackage fake\n",
+			expected: true,
+		},
+		"GeneratedWindows": {
+			source:   "// Code generated by mockgen. DO NOT EDIT.\r\n\r\npackage fake\r\n",
+			expected: true,
+		},
+		"AfterPackageClause": {
+			source: "package fake\n\n// Code generated by hand. DO NOT EDIT.\n",
+		},
+		"Malformed": {
+			source: "// Code generated by hand, do not edit.\npackage fake\n",
+		},
+	}
 
-	lines, err := locCounter(strings.NewReader(fakeFile))
-	assert.NoError(t, err)
-	assert.Equal(t, 5, lines)
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testcase.expected, isGenerated([]byte(testcase.source)))
+		})
+	}
+}
 
-	locCounterBufferSize = 32 * 1024
+func Test_CountSuppressions(t *testing.T) {
+	testcases := map[string]struct {
+		source             string
+		expectedDirectives int
+		expected           map[string]int
+	}{
+		"None": {
+			source: "package fake\n\n// Not a nolint directive.\nvar a = 1\n",
+		},
+		"Blanket": {
+			source:             "package fake\n\nvar a = 1 //nolint\nvar b = 2 // nolint\n",
+			expectedDirectives: 2,
+			expected:           map[string]int{SuppressAllLinters: 2},
+		},
+		"Specific": {
+			source:             "package fake\n\n//nolint:golint,unused // Legacy code.\nfunc a() {}\n\nvar b = 2 //nolint:golint\n",
+			expectedDirectives: 2,
+			expected:           map[string]int{"golint": 2, "unused": 1},
+		},
+		"StringLiterals": {
+			source: "package fake\n\nvar a = \"//nolint\"\nvar b = `\n//nolint:golint\n`\n",
+		},
+		"Lookalikes": {
+			source: "package fake\n\n//nolintplease\n/* nolint */\nvar a = 1\n",
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			directives, suppressions := countSuppressions([]byte(testcase.source))
+			assert.Equal(t, testcase.expectedDirectives, directives)
+			assert.Equal(t, testcase.expected, suppressions)
+		})
+	}
 }
@@ -1,8 +1,14 @@
 package report
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"sort"
 	"sync"
 	"testing"
 
@@ -17,11 +23,285 @@ func Test_Parse(t *testing.T) {
 		opts:   &LintOpts{excludeDirs: map[string]struct{}{"my_exclude": {}, "vendor": {}}},
 	}
 
-	project, err := parser.parse(filepath.Join("testdata", "project"))
+	project, err := parser.parse(context.Background(), filepath.Join("testdata", "project"))
 	require.NoError(t, err, "Must be able to parse the project without errors.")
 	assert.Equal(t, createParsedProject(), project, "Should have returned the expected project structure.")
 }
 
+func Test_ParseTestFiles(t *testing.T) {
+	projectPath, err := ioutil.TempDir("", "goality-parse")
+	require.NoError(t, err, "Must be able to create a temporary project directory.")
+
+	defer func() { _ = os.RemoveAll(projectPath) }()
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(projectPath, "foo.go"), []byte("package foo\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(projectPath, "foo_test.go"), []byte("package foo\n"), 0644))
+
+	parser := &parser{
+		logger: logrus.New(),
+		opts:   &LintOpts{excludeDirs: map[string]struct{}{}},
+	}
+
+	project, err := parser.parse(context.Background(), projectPath)
+	require.NoError(t, err, "Must be able to parse the project without errors.")
+	assert.False(t, project.root.Files["foo.go"].IsTest)
+	assert.True(t, project.root.Files["foo_test.go"].IsTest)
+}
+
+func Test_ParseExclusions(t *testing.T) {
+	projectPath, err := ioutil.TempDir("", "goality-parse")
+	require.NoError(t, err, "Must be able to create a temporary project directory.")
+
+	defer func() { _ = os.RemoveAll(projectPath) }()
+
+	for _, file := range []string{
+		"foo.go",
+		"foo_mock.go",
+		"legacy/foo.go",
+		"internal/legacy/foo.go",
+		"internal/legacy/sub/foo.go",
+		"internal/foo_mock.go",
+		"vendor/foo.go",
+	} {
+		filePath := filepath.Join(projectPath, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+		require.NoError(t, ioutil.WriteFile(filePath, []byte("package foo\n"), 0644))
+	}
+
+	excluded, err := newPathMatcher("internal/legacy/**")
+	require.NoError(t, err)
+	require.NoError(t, excluded.addRegexps(`_mock\.go$`))
+
+	parser := &parser{
+		logger:   logrus.New(),
+		opts:     &LintOpts{excludeDirs: map[string]struct{}{"vendor": {}}},
+		excluded: excluded,
+	}
+
+	project, err := parser.parse(context.Background(), projectPath)
+	require.NoError(t, err, "Must be able to parse the project without errors.")
+	assert.Equal(t, []string{"foo_mock.go", "internal/foo_mock.go", "internal/legacy", "vendor"}, project.Excluded())
+	assert.Contains(t, project.root.Files, "foo.go")
+	assert.Contains(t, project.root.SubDirectories, "legacy")
+	assert.Empty(t, project.root.SubDirectories["internal"].Files)
+	assert.Empty(t, project.root.SubDirectories["internal"].SubDirectories)
+}
+
+func Test_ParseIgnoreFiles(t *testing.T) {
+	repoPath, err := ioutil.TempDir("", "goality-parse")
+	require.NoError(t, err, "Must be able to create a temporary repository directory.")
+
+	defer func() { _ = os.RemoveAll(repoPath) }()
+
+	for file, content := range map[string]string{
+		".git/info/exclude":               "/project/local.go\n",
+		".git/objects/foo.go":             "package foo\n",
+		".gitignore":                      "*.tmp.go\n",
+		"project/foo.go":                  "package foo\n",
+		"project/foo.tmp.go":              "package foo\n",
+		"project/local.go":                "package foo\n",
+		"project/.goalityignore":          "legacy/\n",
+		"project/legacy/foo.go":           "package foo\n",
+		"project/api/.gitignore":          "*.pb.go\n!keep.pb.go\n",
+		"project/api/foo.pb.go":           "package foo\n",
+		"project/api/keep.pb.go":          "package foo\n",
+		"project/.cache/build/foo.go":     "package foo\n",
+		"project/.cache/build/.gitignore": "*\n",
+	} {
+		filePath := filepath.Join(repoPath, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+		require.NoError(t, ioutil.WriteFile(filePath, []byte(content), 0644))
+	}
+
+	parser := &parser{
+		logger: logrus.New(),
+		opts:   &LintOpts{excludeDirs: map[string]struct{}{}},
+	}
+
+	project, err := parser.parse(context.Background(), filepath.Join(repoPath, "project"))
+	require.NoError(t, err, "Must be able to parse the project without errors.")
+	assert.Equal(t, []string{".cache/build/foo.go", "api/foo.pb.go", "foo.tmp.go", "legacy", "local.go"}, project.Excluded())
+	assert.Len(t, project.root.Files, 1)
+	assert.Contains(t, project.root.Files, "foo.go")
+	assert.Len(t, project.root.SubDirectories["api"].Files, 1)
+	assert.Contains(t, project.root.SubDirectories["api"].Files, "keep.pb.go")
+
+	parser.opts = &LintOpts{excludeDirs: map[string]struct{}{}, noIgnoreFiles: true}
+
+	project, err = parser.parse(context.Background(), repoPath)
+	require.NoError(t, err, "Must be able to parse the project without errors.")
+	assert.Empty(t, project.Excluded())
+	assert.Contains(t, project.root.SubDirectories, ".git")
+	assert.Len(t, project.root.SubDirectories["project"].Files, 3)
+}
+
+func Test_ParseTargets(t *testing.T) {
+	projectPath, err := ioutil.TempDir("", "goality-parse")
+	require.NoError(t, err, "Must be able to create a temporary project directory.")
+
+	defer func() { _ = os.RemoveAll(projectPath) }()
+
+	for file, content := range map[string]string{
+		"runner.go":         "package foo\n",
+		"runner_unix.go":    "//go:build !windows\n\npackage foo\n",
+		"runner_windows.go": "package foo\n",
+		"runner_plan9.go":   "package foo\n",
+	} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(projectPath, file), []byte(content), 0644))
+	}
+
+	var (
+		linux   = Target{GOOS: "linux", GOARCH: "amd64"}
+		windows = Target{GOOS: "windows", GOARCH: "amd64"}
+	)
+
+	parser := &parser{
+		logger: logrus.New(),
+		opts:   &LintOpts{excludeDirs: map[string]struct{}{}, targets: []Target{linux, windows}},
+	}
+
+	project, err := parser.parse(context.Background(), projectPath)
+	require.NoError(t, err, "Must be able to parse the project without errors.")
+	assert.Equal(t, []Target{linux, windows}, project.Targets())
+	require.Len(t, project.root.Files, 3, "Should have skipped files that are not part of any target's build.")
+	assert.Equal(t, []Target{linux, windows}, project.root.Files["runner.go"].Targets)
+	assert.Equal(t, []Target{linux}, project.root.Files["runner_unix.go"].Targets)
+	assert.Equal(t, []Target{windows}, project.root.Files["runner_windows.go"].Targets)
+}
+
+func Test_ParsePackages(t *testing.T) {
+	projectPath, err := ioutil.TempDir("", "goality-parse")
+	require.NoError(t, err, "Must be able to create a temporary project directory.")
+
+	defer func() { _ = os.RemoveAll(projectPath) }()
+
+	for file, content := range map[string]string{
+		"go.mod":             "module example.com/project\n\ngo 1.14\n",
+		"main.go":            "package main\n\nimport _ \"example.com/project/api\"\n\nfunc main() {}\n",
+		"api/api.go":         "package api\n\nimport _ \"example.com/project/api/model\"\n",
+		"api/model/model.go": "package model\n",
+		"docs/README":        "Nothing to see here.\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(projectPath, file)), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(projectPath, file), []byte(content), 0644))
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	parser := &parser{
+		logger: logger,
+		opts:   &LintOpts{excludeDirs: map[string]struct{}{}},
+	}
+
+	project, err := parser.parse(context.Background(), projectPath)
+	require.NoError(t, err, "Must be able to parse the project without errors.")
+	packages, err := project.listPackages(context.Background(), logger, os.Environ(), nil)
+	require.NoError(t, err)
+	require.Len(t, packages, 3)
+
+	project.addPackages(packages)
+
+	assert.Equal(t, &Package{
+		ImportPath: "example.com/project/api",
+		Path:       "api",
+		Imports:    []string{"example.com/project/api/model"},
+		ImportedBy: []string{"example.com/project"},
+		Depth:      1,
+	}, project.Directory("api").Package)
+	assert.Nil(t, project.Directory("docs").Package)
+
+	view := project.GenerateView(WithDepth(1))
+	require.Len(t, view.Packages, 3)
+	assert.Equal(t, CouplingStats{Packages: 1, Efferent: 1, Instability: 1, MaxDepth: 2}, view.SubViews["."].Coupling())
+	assert.Equal(t, CouplingStats{Packages: 2, Afferent: 1, MaxDepth: 1}, view.SubViews["api/..."].Coupling())
+}
+
+func Test_CheckArchitecture(t *testing.T) {
+	projectPath, err := ioutil.TempDir("", "goality-parse")
+	require.NoError(t, err, "Must be able to create a temporary project directory.")
+
+	defer func() { _ = os.RemoveAll(projectPath) }()
+
+	for file, content := range map[string]string{
+		"go.mod":                 "module example.com/project\n\ngo 1.14\n",
+		"domain/domain.go":       "package domain\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/project/transport\"\n)\n",
+		"domain/domain_test.go":  "package domain\n\nimport _ \"example.com/project/transport\"\n",
+		"transport/transport.go": "package transport\n\nimport _ \"example.com/project/domain\"\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(projectPath, file)), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(projectPath, file), []byte(content), 0644))
+	}
+
+	rulesFile := writeTestRules(t, "rules:\n  - name: layers\n    from: [domain]\n    deny: [transport]\n")
+	defer os.Remove(rulesFile)
+
+	rules, err := loadArchitectureRules(rulesFile)
+	require.NoError(t, err)
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	parser := &parser{
+		logger: logger,
+		opts:   &LintOpts{excludeDirs: map[string]struct{}{}},
+	}
+
+	project, err := parser.parse(context.Background(), projectPath)
+	require.NoError(t, err, "Must be able to parse the project without errors.")
+
+	packages, err := project.listPackages(context.Background(), logger, os.Environ(), nil)
+	require.NoError(t, err)
+
+	project.linters = []string{"unused"}
+	require.NoError(t, project.checkArchitecture(logger, rules, packages))
+	assert.Equal(t, []string{ArchitectureLinter, "unused"}, project.linters)
+
+	issues := project.Directory("domain").Files["domain.go"].Issues[ArchitectureLinter]
+	require.Len(t, issues, 1)
+	assert.Equal(t, `package "domain" must not import "transport" (rule layers)`, issues[0].Text)
+	assert.Equal(t, filepath.Join("domain", "domain.go"), issues[0].FilePath())
+	assert.Equal(t, 6, issues[0].Line())
+
+	assert.Len(t, project.Directory("domain").Files["domain_test.go"].Issues[ArchitectureLinter], 1)
+	assert.Empty(t, project.Directory("transport").Files["transport.go"].Issues[ArchitectureLinter])
+
+	view := project.GenerateView(WithDepth(1))
+	assert.Len(t, view.SubViews["domain/..."].Issues[ArchitectureLinter], 2)
+}
+
+func Test_ParseCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	project, err := ParseContext(ctx, nil, filepath.Join("testdata", "project"), WithoutSignalHandling())
+	assert.True(t, errors.Is(err, context.Canceled), "Should have returned the context's cancellation error.")
+	assert.Nil(t, project)
+
+	var cancellationErr *CancellationError
+	require.True(t, errors.As(err, &cancellationErr), "Should have returned a cancellation error.")
+	assert.Equal(t, ".", cancellationErr.Path)
+}
+
+func Test_LintCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	project := createParsedProject()
+	linter := &linter{
+		logger: logrus.New(),
+		opts:   &LintOpts{noSignalHandling: true},
+	}
+
+	err := linter.lint(ctx, project)
+	assert.True(t, errors.Is(err, context.Canceled), "Should have returned the context's cancellation error.")
+
+	var cancellationErr *CancellationError
+	require.True(t, errors.As(err, &cancellationErr), "Should have returned a cancellation error.")
+	assert.Equal(t, ".", cancellationErr.Path)
+	assert.Equal(t, createParsedProject(), project, "Should not have registered any linter issues.")
+}
+
 func Test_Lint(t *testing.T) {
 	cwd, err := os.Getwd()
 	require.NoError(t, err, "Must be able to determine the current directory.")
@@ -32,7 +312,7 @@ func Test_Lint(t *testing.T) {
 		opts:   &LintOpts{configPath: filepath.Join(cwd, "testdata", "project", ".golangci.yaml")},
 	}
 
-	err = linter.lint(project)
+	err = linter.lint(context.Background(), project)
 	require.NoError(t, err, "Must be able to lint the project without errors.")
 	assert.Equal(t, createLintedProject(), project, "Should have found the expected linter issues.")
 }
@@ -50,7 +330,7 @@ func Test_ResourceAwareness(t *testing.T) {
 		memoryMonitory: testMemoryMonitor,
 	}
 
-	err = linter.lint(project)
+	err = linter.lint(context.Background(), project)
 	require.NoError(t, err, "Must be able to lint the project without errors.")
 	assert.Equal(t, createLintedProject(), project, "Should have found the expected linter issues.")
 }
@@ -67,3 +347,123 @@ func testMemoryMonitor(logger *logrus.Logger, wg *sync.WaitGroup, done chan stru
 		systemMemoryMonitor(logger, wg, done, interrupt)
 	}
 }
+
+func Test_ParseConcurrently(t *testing.T) {
+	if _, err := exec.LookPath("golangci-lint"); err != nil {
+		t.Skip("Requires golangci-lint to be installed.")
+	}
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err, "Must be able to determine the current directory.")
+
+	sourcePath := filepath.Join(cwd, "testdata", "project")
+	lintOpts := []*LintOpts{
+		WithConfig(filepath.Join(sourcePath, ".golangci.yaml")),
+		WithExcludeDirs("my_exclude", "vendor"),
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	reference, err := ParseContext(context.Background(), logger, sourcePath, lintOpts...)
+	require.NoError(t, err, "Must be able to parse the reference project without errors.")
+
+	// Each project is a copy of the test project from which a different set of directories is
+	// removed so that any cross-talk between concurrent analyses is detectable. Each one is its own
+	// module so that the package metrics are determined via 'go list' as well.
+	removedDirs := [][]string{nil, {"bar"}, {"foo"}, {"bar", "foo"}}
+
+	projectPaths := make([]string, len(removedDirs))
+	for idx := range projectPaths {
+		projectPaths[idx], err = ioutil.TempDir("", "goality-parse")
+		require.NoError(t, err, "Must be able to create a temporary project directory.")
+
+		defer func(path string) { _ = os.RemoveAll(path) }(projectPaths[idx])
+
+		copyTestProject(t, sourcePath, projectPaths[idx])
+		require.NoError(t, os.RemoveAll(filepath.Join(projectPaths[idx], "vendor")))
+		for _, dir := range removedDirs[idx] {
+			require.NoError(t, os.RemoveAll(filepath.Join(projectPaths[idx], dir)))
+		}
+
+		goMod := fmt.Sprintf("module example.com/project%d\n\ngo 1.14\n", idx)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(projectPaths[idx], "go.mod"), []byte(goMod), 0644))
+	}
+
+	projects := make([]*Project, len(projectPaths))
+	errs := make([]error, len(projectPaths))
+	wg := sync.WaitGroup{}
+
+	for idx := range projectPaths {
+		wg.Add(1)
+
+		go func(idx int) {
+			defer wg.Done()
+
+			opts := append([]*LintOpts{WithPackageMetrics()}, lintOpts...)
+			projects[idx], errs[idx] = ParseContext(context.Background(), logger, projectPaths[idx], opts...)
+		}(idx)
+	}
+
+	wg.Wait()
+
+	for idx := range projects {
+		require.NoError(t, errs[idx], "Must be able to parse the project without errors.")
+		assert.Equal(t, projectPaths[idx], projects[idx].Path)
+
+		expectedIssues := map[string][]string{}
+		for path, issues := range fileIssues(reference) {
+			if _, err = os.Stat(filepath.Join(projectPaths[idx], path)); err == nil {
+				expectedIssues[path] = issues
+			}
+		}
+
+		assert.Equal(t, expectedIssues, fileIssues(projects[idx]), "Should only have found the issues of its own project.")
+		assert.NotEmpty(t, projects[idx].GenerateView().Packages, "Should have determined the packages of its own project.")
+	}
+
+	newCwd, err := os.Getwd()
+	require.NoError(t, err, "Must be able to determine the current directory.")
+	assert.Equal(t, cwd, newCwd, "Should not have changed the working directory.")
+}
+
+// fileIssues returns the linter and text of the issues of each of the project's files.
+func fileIssues(project *Project) map[string][]string {
+	issues := map[string][]string{}
+	for _, file := range project.Files() {
+		for linter, linterIssues := range file.Issues {
+			for _, issue := range linterIssues {
+				issues[file.Path] = append(issues[file.Path], linter+": "+issue.Text)
+			}
+		}
+
+		sort.Strings(issues[file.Path])
+	}
+
+	return issues
+}
+
+func copyTestProject(t *testing.T, source string, target string) {
+	err := filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return os.MkdirAll(filepath.Join(target, relPath), 0755)
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return ioutil.WriteFile(filepath.Join(target, relPath), content, info.Mode())
+	})
+	require.NoError(t, err, "Must be able to copy the test project.")
+}
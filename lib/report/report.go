@@ -16,13 +16,77 @@ type View struct {
 	Path     string
 	SubViews map[string]*SubView
 	Linters  []string
+	// RateMetric determines which lines are used as the denominator for issue occurrence rates.
+	RateMetric LineMetric
+	// TestCode determines how test code is represented in the SubViews.
+	TestCode TestCodeMode
+	// Excluded lists the relative paths of the directories and files that were skipped.
+	Excluded []string
+	// Target is the platform to which the View is restricted, if any.
+	Target *Target
+	// MinSeverity is the severity below which issues were left out of the View.
+	MinSeverity Severity
+	// SuppressionThreshold is the density of 'nolint' directives, per 1k lines as determined by the
+	// RateMetric, above which a SubView is considered to be suppressing too many issues. A value of
+	// zero disables the threshold.
+	SuppressionThreshold float32
+	// Packages holds a non-recursive SubView for each of the project's Go packages, sorted by path. It
+	// is nil if package metrics were not computed.
+	Packages []*SubView
+
+	complexityThresholds map[ComplexityMetric]int
+}
+
+// ComplexityThreshold returns the value of the given metric above which a function is considered to
+// be too complex.
+func (v *View) ComplexityThreshold(metric ComplexityMetric) int {
+	if threshold, ok := v.complexityThresholds[metric]; ok {
+		return threshold
+	}
+
+	return metric.DefaultThreshold()
+}
+
+// ExceedsSuppressionThreshold reports whether the density of 'nolint' directives in the given SubView
+// is above the View's threshold.
+func (v *View) ExceedsSuppressionThreshold(subView *SubView) bool {
+	return v.SuppressionThreshold > 0 && subView.SuppressionDensity(v.RateMetric) > v.SuppressionThreshold
 }
 
 // SubView represents the aggregated lint results for a single directory and it's subtree.
 type SubView struct {
-	Path      string
-	Issues    map[string][]*result.Issue
-	LineCount int
+	Path             string
+	Issues           map[string][]*result.Issue
+	LineCount        int
+	CommentLineCount int
+	BlankLineCount   int
+
+	// The amount of generated code that was excluded from these results.
+	GeneratedFileCount int
+	GeneratedLineCount int
+
+	// SuppressionDirectives is the number of 'nolint' directives. Suppressions breaks them down by the
+	// linters that they name, so that a directive naming multiple linters is counted for each of
+	// them. Directives that do not name any specific linters are recorded under SuppressAllLinters.
+	SuppressionDirectives int
+	Suppressions          map[string]int
+	// Functions holds the complexity metrics of all functions, sorted by file and line.
+	Functions []*FunctionMetrics
+	// Coverage holds the statement coverage of the files for which coverage data is available. It is
+	// nil if there are none.
+	Coverage *Coverage
+	// Churn holds the changes made to the files over the analysed window of the git history. It is nil
+	// if churn was not computed or if none of the files changed.
+	Churn *Churn
+	// Packages holds the Go packages whose directory is part of this SubView, sorted by path.
+	Packages []*Package
+	// Severities holds the severity of the issues to which one was assigned. It is nil if severities
+	// were neither configured nor reported by golangci-lint.
+	Severities map[*result.Issue]Severity
+
+	// Test holds the part of the results that originates from test files. It is nil if there is no
+	// test code in this SubView.
+	Test *SubView
 
 	linters   []string
 	recursive bool
@@ -38,11 +102,7 @@ func (s *SubView) String() string {
 
 	for idx := range s.linters {
 		issues := s.Issues[s.linters[idx]]
-		occurenceRate := float32(0.0)
-
-		if len(issues) > 0 {
-			occurenceRate = float32(len(issues)) / float32(s.LineCount) * 1000
-		}
+		occurenceRate := s.OccurrenceRate(len(issues), LineMetricCode)
 
 		fmt.Fprintf(printer, "- %s: %.2f issues / 1k LoC\n", s.linters[idx], occurenceRate)
 
@@ -54,10 +114,149 @@ func (s *SubView) String() string {
 	return printer.String()
 }
 
+// Lines returns the number of lines of the given kind.
+func (s *SubView) Lines(metric LineMetric) int {
+	switch metric {
+	case LineMetricCodeAndComments:
+		return s.LineCount + s.CommentLineCount
+	case LineMetricTotal:
+		return s.LineCount + s.CommentLineCount + s.BlankLineCount
+	default:
+		return s.LineCount
+	}
+}
+
+// OccurrenceRate returns the number of issues per 1k lines of the given kind.
+func (s *SubView) OccurrenceRate(issueCount int, metric LineMetric) float32 {
+	if issueCount == 0 {
+		return 0
+	}
+
+	return 1000 * float32(issueCount) / float32(s.Lines(metric))
+}
+
+// SuppressionCount returns the total number of 'nolint' directives.
+func (s *SubView) SuppressionCount() int {
+	return s.SuppressionDirectives
+}
+
+// SuppressionDensity returns the number of 'nolint' directives per 1k lines of the given kind.
+func (s *SubView) SuppressionDensity(metric LineMetric) float32 {
+	return s.OccurrenceRate(s.SuppressionCount(), metric)
+}
+
+// Production returns the part of the results that originates from non-test files.
+func (s *SubView) Production() *SubView {
+	if s.Test == nil {
+		return s
+	}
+
+	testIssues := map[*result.Issue]struct{}{}
+	for _, issues := range s.Test.Issues {
+		for _, issue := range issues {
+			testIssues[issue] = struct{}{}
+		}
+	}
+
+	production := &SubView{
+		Path:             s.Path,
+		Issues:           map[string][]*result.Issue{},
+		LineCount:        s.LineCount - s.Test.LineCount,
+		CommentLineCount: s.CommentLineCount - s.Test.CommentLineCount,
+		BlankLineCount:   s.BlankLineCount - s.Test.BlankLineCount,
+
+		GeneratedFileCount: s.GeneratedFileCount,
+		GeneratedLineCount: s.GeneratedLineCount,
+
+		Coverage: s.Coverage.subtract(s.Test.Coverage),
+		Churn:    s.Churn.subtract(s.Test.Churn),
+		Packages: s.Packages,
+
+		Severities: s.Severities,
+
+		linters:   s.linters,
+		recursive: s.recursive,
+	}
+
+	for linter, issues := range s.Issues {
+		for _, issue := range issues {
+			if _, ok := testIssues[issue]; !ok {
+				production.Issues[linter] = append(production.Issues[linter], issue)
+			}
+		}
+	}
+
+	testFunctions := map[*FunctionMetrics]struct{}{}
+	for _, function := range s.Test.Functions {
+		testFunctions[function] = struct{}{}
+	}
+
+	for _, function := range s.Functions {
+		if _, ok := testFunctions[function]; !ok {
+			production.Functions = append(production.Functions, function)
+		}
+	}
+
+	production.SuppressionDirectives = s.SuppressionDirectives - s.Test.SuppressionDirectives
+
+	for linter, count := range s.Suppressions {
+		if count -= s.Test.Suppressions[linter]; count > 0 {
+			if production.Suppressions == nil {
+				production.Suppressions = map[string]int{}
+			}
+
+			production.Suppressions[linter] = count
+		}
+	}
+
+	return production
+}
+
+// TestCodeMode determines how test code is represented in a View.
+type TestCodeMode uint8
+
+const (
+	// TestCodeInclude treats test code the same as any other code.
+	TestCodeInclude TestCodeMode = iota
+	// TestCodeExclude removes test code from all results.
+	TestCodeExclude
+	// TestCodeSplit includes test code but signals that it should be presented separately from the
+	// production code. The test-only results are available via each SubView's Test field.
+	TestCodeSplit
+)
+
+// ParseTestCodeMode returns the TestCodeMode corresponding to the given name.
+func ParseTestCodeMode(name string) (TestCodeMode, bool) {
+	switch name {
+	case "include":
+		return TestCodeInclude, true
+	case "exclude":
+		return TestCodeExclude, true
+	case "split":
+		return TestCodeSplit, true
+	default:
+		return TestCodeInclude, false
+	}
+}
+
 // ViewOpts contains options for generating a View.
 type ViewOpts struct {
-	depth int
-	paths []string
+	depth                int
+	paths                []string
+	rateMetric           LineMetric
+	testCode             TestCodeMode
+	suppressionThreshold float32
+	complexityThresholds map[ComplexityMetric]int
+	filter               subViewFilter
+}
+
+// subViewFilter determines which files contribute to a SubView.
+type subViewFilter struct {
+	includeGenerated bool
+	// When set only files participating in the build for this target are taken into account.
+	target Target
+	// Issues of a lower severity are left out.
+	minSeverity Severity
 }
 
 // WithDepth generates a View containing SubViews rooted at directories at the specified depth.
@@ -65,6 +264,67 @@ func WithDepth(depth int) *ViewOpts {
 	return &ViewOpts{depth: depth}
 }
 
+// WithRateMetric generates a View for which issue occurrence rates are computed relative to the
+// specified kind of lines. By default only lines of code are considered.
+func WithRateMetric(metric LineMetric) *ViewOpts {
+	return &ViewOpts{
+		depth:      -1,
+		rateMetric: metric,
+	}
+}
+
+// WithTestCode generates a View in which test code is represented as specified.
+func WithTestCode(mode TestCodeMode) *ViewOpts {
+	return &ViewOpts{
+		depth:    -1,
+		testCode: mode,
+	}
+}
+
+// WithGeneratedCode generates a View in which generated code is treated the same as any other code
+// instead of being excluded.
+func WithGeneratedCode() *ViewOpts {
+	return &ViewOpts{
+		depth:  -1,
+		filter: subViewFilter{includeGenerated: true},
+	}
+}
+
+// WithTarget generates a View that only takes into account the files that participate in the build
+// for the specified target.
+func WithTarget(target Target) *ViewOpts {
+	return &ViewOpts{
+		depth:  -1,
+		filter: subViewFilter{target: target},
+	}
+}
+
+// WithMinSeverity generates a View that only contains the issues of at least the given severity.
+func WithMinSeverity(severity Severity) *ViewOpts {
+	return &ViewOpts{
+		depth:  -1,
+		filter: subViewFilter{minSeverity: severity},
+	}
+}
+
+// WithSuppressionThreshold generates a View that flags the SubViews in which the density of 'nolint'
+// directives, per 1k lines, exceeds the given threshold.
+func WithSuppressionThreshold(density float32) *ViewOpts {
+	return &ViewOpts{
+		depth:                -1,
+		suppressionThreshold: density,
+	}
+}
+
+// WithComplexityThreshold generates a View in which functions are considered to be too complex when
+// the given metric exceeds the specified threshold instead of the metric's default.
+func WithComplexityThreshold(metric ComplexityMetric, threshold int) *ViewOpts {
+	return &ViewOpts{
+		depth:                -1,
+		complexityThresholds: map[ComplexityMetric]int{metric: threshold},
+	}
+}
+
 // WithPaths generates a View containing SubViews rooted at the specified paths.
 func WithPaths(paths ...string) *ViewOpts {
 	return &ViewOpts{
@@ -77,8 +337,68 @@ func WithPaths(paths ...string) *ViewOpts {
 type Project struct {
 	Path string
 
-	linters []string
-	root    *Directory
+	linters  []string
+	root     *Directory
+	excluded map[string]struct{}
+	targets  []Target
+
+	// reportedSeverities holds the severities that golangci-lint assigned to issues based on the
+	// 'severity' section of the project's configuration.
+	reportedSeverities map[*result.Issue]string
+}
+
+// Targets returns the platforms for which the project was analysed.
+func (p *Project) Targets() []Target {
+	return p.targets
+}
+
+// Excluded returns the sorted, slash-separated relative paths of all directories and files that were
+// skipped during the analysis of the project. The content of skipped directories is not listed.
+func (p *Project) Excluded() []string {
+	var excluded []string
+	for path := range p.excluded {
+		excluded = append(excluded, path)
+	}
+
+	sort.Strings(excluded)
+
+	return excluded
+}
+
+// isExcluded determines whether the given relative path is, or is located under, a skipped path.
+func (p *Project) isExcluded(path string) bool {
+	for path = filepath.ToSlash(path); path != "." && path != "/" && path != ""; path = filepath.ToSlash(filepath.Dir(path)) {
+		if _, ok := p.excluded[path]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Files returns all the files of the project, sorted by their relative path.
+func (p *Project) Files() []*File {
+	var files []*File
+	if p.root != nil {
+		files = p.root.files()
+	}
+
+	sort.Slice(files, func(i int, j int) bool { return files[i].Path < files[j].Path })
+
+	return files
+}
+
+func (d *Directory) files() []*File {
+	var files []*File
+	for _, file := range d.Files {
+		files = append(files, file)
+	}
+
+	for _, subDirectory := range d.SubDirectories {
+		files = append(files, subDirectory.files()...)
+	}
+
+	return files
 }
 
 // Directory returns the information for the directory located at the given relative path in the
@@ -90,7 +410,11 @@ func (p *Project) Directory(path string) *Directory {
 // SubView returns the aggregate linter results for the directory located at the given relative path
 // in the project (if any exists).
 func (p *Project) SubView(path string) *SubView {
-	return p.root.subViewPath(strings.Split(filepath.Clean(path), string(os.PathSeparator)))
+	return p.subView(path, subViewFilter{})
+}
+
+func (p *Project) subView(path string, filter subViewFilter) *SubView {
+	return p.root.subViewPath(strings.Split(filepath.Clean(path), string(os.PathSeparator)), filter)
 }
 
 // GenerateView returns the aggregated analysis report for the sub-tree of the project rooted at the
@@ -100,22 +424,51 @@ func (p *Project) GenerateView(opts ...*ViewOpts) *View {
 
 	var subViews []*SubView
 	if opt.depth >= 0 || len(opt.paths) == 0 {
-		subViews = append(subViews, p.root.subViewDepth(opt.depth)...)
+		subViews = append(subViews, p.root.subViewDepth(opt.depth, opt.filter)...)
 	}
 
 	for _, path := range opt.paths {
-		subViews = append(subViews, p.SubView(path))
+		subViews = append(subViews, p.subView(path, opt.filter))
 	}
 
 	view := &View{
-		Path:     p.Path,
-		SubViews: map[string]*SubView{},
-		Linters:  p.linters,
+		Path:       p.Path,
+		SubViews:   map[string]*SubView{},
+		Linters:    p.linters,
+		RateMetric: opt.rateMetric,
+		TestCode:   opt.testCode,
+		Excluded:   p.Excluded(),
+
+		MinSeverity: opt.filter.minSeverity,
+
+		SuppressionThreshold: opt.suppressionThreshold,
+
+		complexityThresholds: opt.complexityThresholds,
+	}
+	if opt.filter.target != (Target{}) {
+		target := opt.filter.target
+		view.Target = &target
 	}
+
 	for _, subView := range subViews {
+		if opt.testCode == TestCodeExclude {
+			subView = subView.Production()
+		}
+
 		view.SubViews[subView.Path] = subView
 	}
 
+	for _, dir := range p.root.packageDirectories() {
+		subView := dir.subViewSelf(opt.filter)
+		if opt.testCode == TestCodeExclude {
+			subView = subView.Production()
+		}
+
+		view.Packages = append(view.Packages, subView)
+	}
+
+	sort.Slice(view.Packages, func(i int, j int) bool { return view.Packages[i].Path < view.Packages[j].Path })
+
 	return view
 }
 
@@ -125,17 +478,48 @@ type Directory struct {
 	Path           string
 	SubDirectories map[string]*Directory
 	Files          map[string]*File
+	// Package is the Go package located in this directory, if any and if package metrics were computed.
+	Package *Package
+
+	// Cached instances of the reports for this folder to prevent re-computation.
+	views map[subViewCacheKey]*SubView
+}
 
-	// Cached instance of the report for this folder to prevent re-computation.
-	recursiveView *SubView
-	selfView      *SubView
+type subViewCacheKey struct {
+	filter    subViewFilter
+	recursive bool
 }
 
 // File represents the analysis results for a single given file.
 type File struct {
-	Path      string
-	LineCount int
-	Issues    map[string][]*result.Issue
+	Path             string
+	LineCount        int
+	CommentLineCount int
+	BlankLineCount   int
+	Issues           map[string][]*result.Issue
+	// IsTest indicates whether this is a '_test.go' file.
+	IsTest bool
+	// IsGenerated indicates whether this file contains generated code, either based on the standard
+	// 'Code generated ... DO NOT EDIT.' header or on user-specified patterns.
+	IsGenerated bool
+	// Targets lists the platforms, among those of the analysis, for whose build this file is used.
+	Targets []Target
+	// SuppressionDirectives is the number of 'nolint' directives in this file and Suppressions the
+	// number of those directives that name each linter.
+	SuppressionDirectives int
+	Suppressions          map[string]int
+	// Functions holds the complexity metrics of the functions declared in this file.
+	Functions []*FunctionMetrics
+	// Coverage holds the statement coverage of this file. It is nil if no coverage data is available.
+	Coverage *Coverage
+	// Churn holds the changes made to this file. It is nil if churn was not computed or if the file did
+	// not change over the analysed window.
+	Churn *Churn
+	// Severities holds the severity of this file's issues to which one was assigned. It is nil if
+	// severities were neither configured nor reported by golangci-lint.
+	Severities map[*result.Issue]Severity
+	// Hash is the hex-encoded SHA-256 checksum of the file's content at the time of the analysis.
+	Hash string
 }
 
 func (d *Directory) hasFiles(recursive bool) bool {
@@ -167,72 +551,108 @@ func (d *Directory) getDirectory(path []string) *Directory {
 	return subDir.getDirectory(path[1:])
 }
 
-func (d *Directory) subViewDepth(depth int) []*SubView {
+func (d *Directory) subViewDepth(depth int, filter subViewFilter) []*SubView {
 	if depth <= 0 {
-		return []*SubView{d.subViewRecursive()}
+		return []*SubView{d.subViewRecursive(filter)}
 	}
 
-	views := []*SubView{d.subViewSelf()}
+	views := []*SubView{d.subViewSelf(filter)}
 	for _, subDir := range d.SubDirectories {
-		views = append(views, subDir.subViewDepth(depth-1)...)
+		views = append(views, subDir.subViewDepth(depth-1, filter)...)
 	}
 
 	return views
 }
 
-func (d *Directory) subViewPath(path []string) *SubView {
+func (d *Directory) subViewPath(path []string, filter subViewFilter) *SubView {
 	if len(path) > 0 {
 		subDir, ok := d.SubDirectories[path[0]]
 		if ok {
-			return subDir.subViewPath(path[1:])
+			return subDir.subViewPath(path[1:], filter)
 		}
 
 		file, ok := d.Files[path[0]]
 		if ok {
-			return file.subView()
+			return file.subView(filter)
 		}
 
 		return nil
 	}
 
-	return d.subViewRecursive()
+	return d.subViewRecursive(filter)
 }
 
-func (d *Directory) subViewRecursive() *SubView {
-	if d.recursiveView == nil {
-		childReports := []*SubView{d.subViewSelf()}
+func (d *Directory) subViewRecursive(filter subViewFilter) *SubView {
+	key := subViewCacheKey{filter: filter, recursive: true}
+	if d.views[key] == nil {
+		childReports := []*SubView{d.subViewSelf(filter)}
 		for _, d := range d.SubDirectories {
-			childReports = append(childReports, d.subViewRecursive())
+			childReports = append(childReports, d.subViewRecursive(filter))
 		}
 
-		d.recursiveView = fuse(childReports...)
-		d.recursiveView.Path = d.Path + "/..."
-		d.recursiveView.recursive = true
-
-		for _, issues := range d.recursiveView.Issues {
-			sort.Sort(sortableIssues(issues))
-		}
+		view := fuse(childReports...)
+		view.finalise(d.Path+"/...", true)
+		d.cacheView(key, view)
 	}
 
-	return d.recursiveView
+	return d.views[key]
 }
 
-func (d *Directory) subViewSelf() *SubView {
-	if d.selfView == nil {
+func (d *Directory) subViewSelf(filter subViewFilter) *SubView {
+	key := subViewCacheKey{filter: filter}
+	if d.views[key] == nil {
 		var childReports []*SubView
 		for _, f := range d.Files {
-			childReports = append(childReports, f.subView())
+			childReports = append(childReports, f.subView(filter))
 		}
 
-		d.selfView = fuse(childReports...)
-		d.selfView.Path = d.Path
-
-		for _, issues := range d.selfView.Issues {
-			sort.Sort(sortableIssues(issues))
+		view := fuse(childReports...)
+		if d.Package != nil {
+			view.Packages = []*Package{d.Package}
 		}
+
+		view.finalise(d.Path, false)
+		d.cacheView(key, view)
+	}
+
+	return d.views[key]
+}
+
+func (d *Directory) packageDirectories() []*Directory {
+	var dirs []*Directory
+	if d.Package != nil {
+		dirs = append(dirs, d)
+	}
+
+	for _, subDir := range d.SubDirectories {
+		dirs = append(dirs, subDir.packageDirectories()...)
 	}
 
-	return d.selfView
+	return dirs
+}
+
+func (d *Directory) cacheView(key subViewCacheKey, view *SubView) {
+	if d.views == nil {
+		d.views = map[subViewCacheKey]*SubView{}
+	}
+
+	d.views[key] = view
+}
+
+func (s *SubView) finalise(path string, recursive bool) {
+	s.Path = path
+	s.recursive = recursive
+
+	for _, issues := range s.Issues {
+		sort.Sort(sortableIssues(issues))
+	}
+
+	sort.Sort(sortableFunctions(s.Functions))
+	sort.Sort(sortablePackages(s.Packages))
+
+	if s.Test != nil {
+		s.Test.finalise(path, recursive)
+	}
 }
 
 func (p *Project) addIssue(logger *logrus.Logger, issue *result.Issue) {
@@ -240,6 +660,11 @@ func (p *Project) addIssue(logger *logrus.Logger, issue *result.Issue) {
 		p.root = &Directory{}
 	}
 
+	if p.isExcluded(issue.FilePath()) {
+		logger.Debugf("Ignoring issue %+v in excluded path.", issue)
+		return
+	}
+
 	p.root.addIssue(logger, strings.Split(issue.FilePath(), string(os.PathSeparator)), issue)
 }
 
@@ -261,31 +686,128 @@ func (d *Directory) addIssue(logger *logrus.Logger, path []string, issue *result
 	logger.Warnf("Entry %q for issue %+v does not exist in %q.", path[0], issue, d.Path)
 }
 
-func (f *File) subView() *SubView {
-	return &SubView{
-		Path:      f.Path,
-		Issues:    f.Issues,
-		LineCount: f.LineCount,
+func (f *File) subView(filter subViewFilter) *SubView {
+	if filter.target != (Target{}) && !f.hasTarget(filter.target) {
+		return &SubView{
+			Path:   f.Path,
+			Issues: map[string][]*result.Issue{},
+		}
+	}
+
+	if f.IsGenerated && !filter.includeGenerated {
+		return &SubView{
+			Path:               f.Path,
+			Issues:             map[string][]*result.Issue{},
+			GeneratedFileCount: 1,
+			GeneratedLineCount: f.LineCount,
+		}
+	}
+
+	issues := f.Issues
+	if filter.minSeverity > SeverityInfo {
+		issues = map[string][]*result.Issue{}
+		for linter, linterIssues := range f.Issues {
+			for _, issue := range linterIssues {
+				if lookupSeverity(f.Severities, issue) >= filter.minSeverity {
+					issues[linter] = append(issues[linter], issue)
+				}
+			}
+		}
+	}
+
+	subView := &SubView{
+		Path:             f.Path,
+		Issues:           issues,
+		LineCount:        f.LineCount,
+		CommentLineCount: f.CommentLineCount,
+		BlankLineCount:   f.BlankLineCount,
+		Functions:        f.Functions,
+		Coverage:         f.Coverage,
+		Churn:            f.Churn,
+		Severities:       f.Severities,
+
+		SuppressionDirectives: f.SuppressionDirectives,
+		Suppressions:          f.Suppressions,
+	}
+
+	if f.IsTest {
+		test := *subView
+		subView.Test = &test
 	}
+
+	return subView
+}
+
+func (f *File) hasTarget(target Target) bool {
+	for _, fileTarget := range f.Targets {
+		if fileTarget == target {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (f *File) addIssue(issue *result.Issue) {
+	// The same issue is reported once for each target for which a file is linted.
+	for _, existing := range f.Issues[issue.FromLinter] {
+		if existing.Text == issue.Text && existing.Pos == issue.Pos {
+			return
+		}
+	}
+
 	f.Issues[issue.FromLinter] = append(f.Issues[issue.FromLinter], issue)
 }
 
 func fuse(subViews ...*SubView) *SubView {
 	fused := &SubView{Issues: map[string][]*result.Issue{}}
 
+	var tests []*SubView
+
 	for _, subView := range subViews {
 		if subView == nil {
 			continue
 		}
 
+		if subView.Test != nil {
+			tests = append(tests, subView.Test)
+		}
+
 		for linter, issues := range subView.Issues {
 			fused.Issues[linter] = append(fused.Issues[linter], issues...)
 		}
 
 		fused.LineCount += subView.LineCount
+		fused.CommentLineCount += subView.CommentLineCount
+		fused.BlankLineCount += subView.BlankLineCount
+		fused.GeneratedFileCount += subView.GeneratedFileCount
+		fused.GeneratedLineCount += subView.GeneratedLineCount
+		fused.Functions = append(fused.Functions, subView.Functions...)
+		fused.Coverage = fused.Coverage.add(subView.Coverage)
+		fused.Churn = fused.Churn.add(subView.Churn)
+		fused.Packages = append(fused.Packages, subView.Packages...)
+
+		for issue, severity := range subView.Severities {
+			if fused.Severities == nil {
+				fused.Severities = map[*result.Issue]Severity{}
+			}
+
+			fused.Severities[issue] = severity
+		}
+
+		fused.SuppressionDirectives += subView.SuppressionDirectives
+
+		for linter, count := range subView.Suppressions {
+			if fused.Suppressions == nil {
+				fused.Suppressions = map[string]int{}
+			}
+
+			fused.Suppressions[linter] += count
+		}
+	}
+
+	if len(tests) > 0 {
+		fused.Test = fuse(tests...)
 	}
 
 	return fused
@@ -301,6 +823,36 @@ func aggregateViewOpts(opts ...*ViewOpts) *ViewOpts {
 			aggregate.depth = opt.depth
 		}
 
+		if opt.rateMetric != LineMetricCode {
+			aggregate.rateMetric = opt.rateMetric
+		}
+
+		if opt.testCode != TestCodeInclude {
+			aggregate.testCode = opt.testCode
+		}
+
+		if opt.suppressionThreshold > 0 {
+			aggregate.suppressionThreshold = opt.suppressionThreshold
+		}
+
+		for metric, threshold := range opt.complexityThresholds {
+			if aggregate.complexityThresholds == nil {
+				aggregate.complexityThresholds = map[ComplexityMetric]int{}
+			}
+
+			aggregate.complexityThresholds[metric] = threshold
+		}
+
+		aggregate.filter.includeGenerated = aggregate.filter.includeGenerated || opt.filter.includeGenerated
+
+		if opt.filter.target != (Target{}) {
+			aggregate.filter.target = opt.filter.target
+		}
+
+		if opt.filter.minSeverity > aggregate.filter.minSeverity {
+			aggregate.filter.minSeverity = opt.filter.minSeverity
+		}
+
 		paths = append(paths, opt.paths...)
 	}
 
@@ -79,14 +79,65 @@ var (
 	}
 )
 
+var (
+	rootMainFunction = &FunctionMetrics{
+		Path:       "file.go",
+		Name:       "main",
+		Line:       10,
+		Cyclomatic: 5,
+		Cognitive:  5,
+		Length:     21,
+		Nesting:    2,
+	}
+	rootRouletteFunction = &FunctionMetrics{
+		Path:       "file.go",
+		Name:       "russianRoulette",
+		Line:       32,
+		Cyclomatic: 2,
+		Cognitive:  1,
+		Length:     6,
+		Nesting:    1,
+	}
+	barUnusedFunction = &FunctionMetrics{
+		Path:       "bar/file.go",
+		Name:       "unusedFunc",
+		Line:       3,
+		Cyclomatic: 1,
+		Length:     3,
+	}
+	fooDirSnowFunction = &FunctionMetrics{
+		Path:       "foo/dir/file.go",
+		Name:       "LetItSnow",
+		Line:       5,
+		Cyclomatic: 2,
+		Cognitive:  1,
+		Length:     8,
+		Nesting:    1,
+	}
+	fooDirUnworthyFunction = &FunctionMetrics{
+		Path:       "foo/dir/file.go",
+		Name:       "unworthy",
+		Line:       14,
+		Cyclomatic: 1,
+		Length:     1,
+	}
+)
+
 func createParsedProject() *Project {
 	cwd, err := os.Getwd()
 	if err != nil {
 		panic(err)
 	}
 
+	targets := []Target{defaultTarget(os.Environ())}
+
 	return &Project{
-		Path: filepath.Join(cwd, "testdata", "project"),
+		Path:    filepath.Join(cwd, "testdata", "project"),
+		targets: targets,
+		excluded: map[string]struct{}{
+			"bar/my_exclude": {},
+			"vendor":         {},
+		},
 		root: &Directory{
 			Path: ".",
 			SubDirectories: map[string]*Directory{
@@ -95,9 +146,13 @@ func createParsedProject() *Project {
 					SubDirectories: map[string]*Directory{},
 					Files: map[string]*File{
 						"file.go": {
-							Path:      "bar/file.go",
-							LineCount: 4,
-							Issues:    map[string][]*result.Issue{},
+							Path:           "bar/file.go",
+							LineCount:      4,
+							BlankLineCount: 1,
+							Issues:         map[string][]*result.Issue{},
+							Targets:        targets,
+							Functions:      []*FunctionMetrics{barUnusedFunction},
+							Hash:           "5d82146abbf9da7a70503d52654c8831479d7a4873c4635e2198826018feac04",
 						},
 					},
 				},
@@ -109,9 +164,13 @@ func createParsedProject() *Project {
 							SubDirectories: map[string]*Directory{},
 							Files: map[string]*File{
 								"file.go": {
-									Path:      "foo/dir/file.go",
-									LineCount: 11,
-									Issues:    map[string][]*result.Issue{},
+									Path:           "foo/dir/file.go",
+									LineCount:      11,
+									BlankLineCount: 3,
+									Issues:         map[string][]*result.Issue{},
+									Targets:        targets,
+									Functions:      []*FunctionMetrics{fooDirSnowFunction, fooDirUnworthyFunction},
+									Hash:           "e0c51fbeb503ba0bffa4e7c181b3f304783ee486a680b4219baa55bc28514757",
 								},
 							},
 						},
@@ -126,9 +185,13 @@ func createParsedProject() *Project {
 			},
 			Files: map[string]*File{
 				"file.go": {
-					Path:      "file.go",
-					LineCount: 32,
-					Issues:    map[string][]*result.Issue{},
+					Path:           "file.go",
+					LineCount:      32,
+					BlankLineCount: 5,
+					Issues:         map[string][]*result.Issue{},
+					Targets:        targets,
+					Functions:      []*FunctionMetrics{rootMainFunction, rootRouletteFunction},
+					Hash:           "6b104e9416f123b45f48699e53ee68274c75442e64b40ec14d170fd181157e17",
 				},
 			},
 		},
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/golangci/golangci-lint/pkg/result"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -80,6 +81,7 @@ func Test_AggregateViewOpts(t *testing.T) {
 				"foo/bar/dir/subDir",
 			},
 		}
+		viewOptsG = WithRateMetric(LineMetricTotal)
 	)
 
 	testcases := map[string]struct {
@@ -100,6 +102,10 @@ func Test_AggregateViewOpts(t *testing.T) {
 			viewOpts: []*ViewOpts{viewOptsD, viewOptsE},
 			expected: &ViewOpts{depth: 1},
 		},
+		"RateMetric": {
+			viewOpts: []*ViewOpts{viewOptsA, viewOptsG, viewOptsD},
+			expected: &ViewOpts{depth: 3, rateMetric: LineMetricTotal},
+		},
 		"DepthsAndPaths": {
 			viewOpts: []*ViewOpts{viewOptsA, viewOptsC, viewOptsE, viewOptsF},
 			expected: &ViewOpts{
@@ -120,3 +126,48 @@ func Test_AggregateViewOpts(t *testing.T) {
 		})
 	}
 }
+
+func Test_AddIssueExcluded(t *testing.T) {
+	project := &Project{
+		root: &Directory{
+			Path: ".",
+			SubDirectories: map[string]*Directory{
+				"foo": {
+					Path:           "foo",
+					SubDirectories: map[string]*Directory{},
+					Files: map[string]*File{
+						"file.go": {Path: "foo/file.go", Issues: map[string][]*result.Issue{}},
+					},
+				},
+			},
+			Files: map[string]*File{},
+		},
+		excluded: map[string]struct{}{"foo/legacy": {}, "foo/file_mock.go": {}},
+	}
+
+	issues := map[string]*result.Issue{
+		"foo/file.go":           {FromLinter: "golint", Pos: token.Position{Filename: "foo/file.go"}},
+		"foo/file_mock.go":      {FromLinter: "golint", Pos: token.Position{Filename: "foo/file_mock.go"}},
+		"foo/legacy/sub/old.go": {FromLinter: "golint", Pos: token.Position{Filename: "foo/legacy/sub/old.go"}},
+	}
+	for _, issue := range issues {
+		project.addIssue(logrus.New(), issue)
+	}
+
+	assert.Equal(t, []string{"foo/file_mock.go", "foo/legacy"}, project.Excluded())
+	assert.Equal(t, map[string][]*result.Issue{"golint": {issues["foo/file.go"]}}, project.root.SubDirectories["foo"].Files["file.go"].Issues)
+}
+
+func Test_AddIssueDuplicate(t *testing.T) {
+	file := &File{Path: "file.go", Issues: map[string][]*result.Issue{}}
+
+	issue := &result.Issue{FromLinter: "golint", Text: "exported func Foo should have comment", Pos: token.Position{Filename: "file.go", Line: 3}}
+	duplicate := *issue
+	other := &result.Issue{FromLinter: "golint", Text: "exported func Bar should have comment", Pos: token.Position{Filename: "file.go", Line: 3}}
+
+	file.addIssue(issue)
+	file.addIssue(&duplicate)
+	file.addIssue(other)
+
+	assert.Equal(t, map[string][]*result.Issue{"golint": {issue, other}}, file.Issues)
+}
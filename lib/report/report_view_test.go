@@ -2,10 +2,12 @@ package report
 
 import (
 	"go/token"
+	"regexp"
 	"testing"
 
 	"github.com/golangci/golangci-lint/pkg/result"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -49,8 +51,9 @@ func Test_Views(t *testing.T) {
 	view := project.GenerateView()
 	require.Len(t, view.SubViews, 1)
 	require.Equal(t, &SubView{
-		Path:      "./...",
-		LineCount: 47,
+		Path:           "./...",
+		LineCount:      47,
+		BlankLineCount: 9,
 		Issues: map[string][]*result.Issue{
 			"govet": {
 				rootGoVetIssue,
@@ -61,6 +64,13 @@ func Test_Views(t *testing.T) {
 				fooDirUnusedIssue,
 			},
 		},
+		Functions: []*FunctionMetrics{
+			barUnusedFunction,
+			rootMainFunction,
+			rootRouletteFunction,
+			fooDirSnowFunction,
+			fooDirUnworthyFunction,
+		},
 		recursive: true,
 	}, view.SubViews["./..."])
 
@@ -71,23 +81,28 @@ func Test_Views(t *testing.T) {
 		Path: project.Path,
 		SubViews: map[string]*SubView{
 			"bar/file.go": {
-				Path:      "bar/file.go",
-				LineCount: 4,
+				Path:           "bar/file.go",
+				LineCount:      4,
+				BlankLineCount: 1,
 				Issues: map[string][]*result.Issue{
 					"unused": {barUnusedIssue},
 				},
+				Functions: []*FunctionMetrics{barUnusedFunction},
 			},
 			"foo/dir/...": {
-				Path:      "foo/dir/...",
-				LineCount: 11,
+				Path:           "foo/dir/...",
+				LineCount:      11,
+				BlankLineCount: 3,
 				Issues: map[string][]*result.Issue{
 					"govet":  {fooDirGoVetIssue},
 					"unused": {fooDirUnusedIssue},
 				},
+				Functions: []*FunctionMetrics{fooDirSnowFunction, fooDirUnworthyFunction},
 				recursive: true,
 			},
 		},
-		Linters: linters,
+		Linters:  linters,
+		Excluded: []string{"bar/my_exclude", "vendor"},
 	}, view)
 
 	// Generate a depth-specific view.
@@ -97,30 +112,366 @@ func Test_Views(t *testing.T) {
 		Path: project.Path,
 		SubViews: map[string]*SubView{
 			".": {
-				Path:      ".",
-				LineCount: 32,
+				Path:           ".",
+				LineCount:      32,
+				BlankLineCount: 5,
 				Issues: map[string][]*result.Issue{
 					"govet": {rootGoVetIssue},
 				},
+				Functions: []*FunctionMetrics{rootMainFunction, rootRouletteFunction},
 			},
 			"bar/...": {
-				Path:      "bar/...",
-				LineCount: 4,
+				Path:           "bar/...",
+				LineCount:      4,
+				BlankLineCount: 1,
 				Issues: map[string][]*result.Issue{
 					"unused": {barUnusedIssue},
 				},
+				Functions: []*FunctionMetrics{barUnusedFunction},
 				recursive: true,
 			},
 			"foo/...": {
-				Path:      "foo/...",
-				LineCount: 11,
+				Path:           "foo/...",
+				LineCount:      11,
+				BlankLineCount: 3,
 				Issues: map[string][]*result.Issue{
 					"govet":  {fooDirGoVetIssue},
 					"unused": {fooDirUnusedIssue},
 				},
+				Functions: []*FunctionMetrics{fooDirSnowFunction, fooDirUnworthyFunction},
 				recursive: true,
 			},
 		},
-		Linters: linters,
+		Linters:  linters,
+		Excluded: []string{"bar/my_exclude", "vendor"},
 	}, view)
 }
+
+func Test_OccurrenceRate(t *testing.T) {
+	subView := &SubView{
+		LineCount:        150,
+		CommentLineCount: 50,
+		BlankLineCount:   50,
+	}
+
+	require.Equal(t, 150, subView.Lines(LineMetricCode))
+	require.Equal(t, 200, subView.Lines(LineMetricCodeAndComments))
+	require.Equal(t, 250, subView.Lines(LineMetricTotal))
+
+	require.Equal(t, float32(20), subView.OccurrenceRate(3, LineMetricCode))
+	require.Equal(t, float32(15), subView.OccurrenceRate(3, LineMetricCodeAndComments))
+	require.Equal(t, float32(12), subView.OccurrenceRate(3, LineMetricTotal))
+	require.Equal(t, float32(0), subView.OccurrenceRate(0, LineMetricTotal))
+	require.Equal(t, float32(0), (&SubView{}).OccurrenceRate(0, LineMetricCode))
+}
+
+func Test_TestCodeViews(t *testing.T) {
+	productionIssue := &result.Issue{
+		FromLinter: "govet",
+		Pos:        token.Position{Filename: "foo/foo.go", Line: 3},
+	}
+	testIssue := &result.Issue{
+		FromLinter: "govet",
+		Pos:        token.Position{Filename: "foo/foo_test.go", Line: 5},
+	}
+
+	project := &Project{
+		linters: []string{"govet"},
+		root: &Directory{
+			Path: ".",
+			SubDirectories: map[string]*Directory{
+				"foo": {
+					Path:           "foo",
+					SubDirectories: map[string]*Directory{},
+					Files: map[string]*File{
+						"foo.go": {
+							Path:           "foo/foo.go",
+							LineCount:      20,
+							BlankLineCount: 2,
+							Issues:         map[string][]*result.Issue{"govet": {productionIssue}},
+						},
+						"foo_test.go": {
+							Path:           "foo/foo_test.go",
+							LineCount:      10,
+							BlankLineCount: 1,
+							Issues:         map[string][]*result.Issue{"govet": {testIssue}},
+							IsTest:         true,
+						},
+					},
+				},
+			},
+			Files: map[string]*File{},
+		},
+	}
+
+	testView := &SubView{
+		Path:           "./...",
+		LineCount:      10,
+		BlankLineCount: 1,
+		Issues:         map[string][]*result.Issue{"govet": {testIssue}},
+		recursive:      true,
+	}
+
+	view := project.GenerateView(WithTestCode(TestCodeSplit))
+	require.Equal(t, TestCodeSplit, view.TestCode)
+	require.Equal(t, &SubView{
+		Path:           "./...",
+		LineCount:      30,
+		BlankLineCount: 3,
+		Issues:         map[string][]*result.Issue{"govet": {productionIssue, testIssue}},
+		Test:           testView,
+		recursive:      true,
+	}, view.SubViews["./..."])
+
+	view = project.GenerateView(WithTestCode(TestCodeExclude))
+	require.Equal(t, &SubView{
+		Path:           "./...",
+		LineCount:      20,
+		BlankLineCount: 2,
+		Issues:         map[string][]*result.Issue{"govet": {productionIssue}},
+		recursive:      true,
+	}, view.SubViews["./..."])
+
+	// Excluding test code should not affect the results for other views.
+	view = project.GenerateView()
+	require.Equal(t, 30, view.SubViews["./..."].LineCount)
+}
+
+func Test_GeneratedCodeViews(t *testing.T) {
+	generatedIssue := &result.Issue{
+		FromLinter: "golint",
+		Pos:        token.Position{Filename: "foo.pb.go", Line: 3},
+	}
+
+	project := &Project{
+		linters: []string{"golint"},
+		root: &Directory{
+			Path:           ".",
+			SubDirectories: map[string]*Directory{},
+			Files: map[string]*File{
+				"foo.go": {
+					Path:      "foo.go",
+					LineCount: 20,
+					Issues:    map[string][]*result.Issue{},
+				},
+				"foo.pb.go": {
+					Path:        "foo.pb.go",
+					LineCount:   200,
+					Issues:      map[string][]*result.Issue{"golint": {generatedIssue}},
+					IsGenerated: true,
+				},
+			},
+		},
+	}
+
+	view := project.GenerateView()
+	require.Equal(t, &SubView{
+		Path:               "./...",
+		LineCount:          20,
+		Issues:             map[string][]*result.Issue{},
+		GeneratedFileCount: 1,
+		GeneratedLineCount: 200,
+		recursive:          true,
+	}, view.SubViews["./..."])
+
+	view = project.GenerateView(WithGeneratedCode())
+	require.Equal(t, &SubView{
+		Path:      "./...",
+		LineCount: 220,
+		Issues:    map[string][]*result.Issue{"golint": {generatedIssue}},
+		recursive: true,
+	}, view.SubViews["./..."])
+}
+
+func Test_TargetViews(t *testing.T) {
+	var (
+		linux   = Target{GOOS: "linux", GOARCH: "amd64"}
+		windows = Target{GOOS: "windows", GOARCH: "amd64"}
+	)
+
+	windowsIssue := &result.Issue{
+		FromLinter: "golint",
+		Pos:        token.Position{Filename: "runner_windows.go", Line: 3},
+	}
+
+	project := &Project{
+		linters: []string{"golint"},
+		targets: []Target{linux, windows},
+		root: &Directory{
+			Path:           ".",
+			SubDirectories: map[string]*Directory{},
+			Files: map[string]*File{
+				"runner.go": {
+					Path:      "runner.go",
+					LineCount: 20,
+					Issues:    map[string][]*result.Issue{},
+					Targets:   []Target{linux, windows},
+				},
+				"runner_unix.go": {
+					Path:      "runner_unix.go",
+					LineCount: 10,
+					Issues:    map[string][]*result.Issue{},
+					Targets:   []Target{linux},
+				},
+				"runner_windows.go": {
+					Path:      "runner_windows.go",
+					LineCount: 30,
+					Issues:    map[string][]*result.Issue{"golint": {windowsIssue}},
+					Targets:   []Target{windows},
+				},
+			},
+		},
+	}
+
+	view := project.GenerateView()
+	require.Nil(t, view.Target)
+	require.Equal(t, 60, view.SubViews["./..."].LineCount)
+
+	view = project.GenerateView(WithTarget(linux))
+	require.Equal(t, &linux, view.Target)
+	require.Equal(t, &SubView{
+		Path:      "./...",
+		LineCount: 30,
+		Issues:    map[string][]*result.Issue{},
+		recursive: true,
+	}, view.SubViews["./..."])
+
+	view = project.GenerateView(WithTarget(windows))
+	require.Equal(t, &windows, view.Target)
+	require.Equal(t, &SubView{
+		Path:      "./...",
+		LineCount: 50,
+		Issues:    map[string][]*result.Issue{"golint": {windowsIssue}},
+		recursive: true,
+	}, view.SubViews["./..."])
+}
+
+func Test_SuppressionViews(t *testing.T) {
+	project := &Project{
+		linters: []string{"golint"},
+		root: &Directory{
+			Path: ".",
+			SubDirectories: map[string]*Directory{
+				"foo": {
+					Path:           "foo",
+					SubDirectories: map[string]*Directory{},
+					Files: map[string]*File{
+						"foo.go": {
+							Path:      "foo/foo.go",
+							LineCount: 100,
+							Issues:    map[string][]*result.Issue{},
+							// '//nolint:golint,unused', '//nolint:golint' and '//nolint'.
+							SuppressionDirectives: 3,
+							Suppressions:          map[string]int{"golint": 2, "unused": 1, SuppressAllLinters: 1},
+						},
+						"foo_test.go": {
+							Path:                  "foo/foo_test.go",
+							LineCount:             100,
+							Issues:                map[string][]*result.Issue{},
+							SuppressionDirectives: 1,
+							Suppressions:          map[string]int{"golint": 1},
+							IsTest:                true,
+						},
+					},
+				},
+				"bar": {
+					Path:           "bar",
+					SubDirectories: map[string]*Directory{},
+					Files: map[string]*File{
+						"bar.go": {
+							Path:      "bar/bar.go",
+							LineCount: 200,
+							Issues:    map[string][]*result.Issue{},
+						},
+					},
+				},
+			},
+			Files: map[string]*File{},
+		},
+	}
+
+	view := project.GenerateView(WithDepth(1), WithSuppressionThreshold(10))
+
+	foo := view.SubViews["foo/..."]
+	require.Equal(t, map[string]int{"golint": 3, "unused": 1, SuppressAllLinters: 1}, foo.Suppressions)
+	require.Equal(t, 4, foo.SuppressionCount(), "Directives naming multiple linters should be counted once.")
+	require.Equal(t, float32(20), foo.SuppressionDensity(LineMetricCode))
+	require.True(t, view.ExceedsSuppressionThreshold(foo))
+	require.Equal(t, map[string]int{"golint": 2, "unused": 1, SuppressAllLinters: 1}, foo.Production().Suppressions)
+	require.Equal(t, 3, foo.Production().SuppressionCount())
+
+	bar := view.SubViews["bar/..."]
+	require.Nil(t, bar.Suppressions)
+	require.Equal(t, float32(0), bar.SuppressionDensity(LineMetricCode))
+	require.False(t, view.ExceedsSuppressionThreshold(bar))
+
+	view = project.GenerateView(WithDepth(1))
+	require.False(t, view.ExceedsSuppressionThreshold(view.SubViews["foo/..."]))
+}
+
+func Test_ComplexityThresholds(t *testing.T) {
+	view := createLintedProject().GenerateView(
+		WithComplexityThreshold(ComplexityCyclomatic, 20),
+		WithComplexityThreshold(ComplexityCyclomatic, 4),
+		WithComplexityThreshold(ComplexityNesting, 1),
+	)
+
+	assert.Equal(t, 4, view.ComplexityThreshold(ComplexityCyclomatic))
+	assert.Equal(t, 1, view.ComplexityThreshold(ComplexityNesting))
+	assert.Equal(t, ComplexityLength.DefaultThreshold(), view.ComplexityThreshold(ComplexityLength))
+
+	stats := view.SubViews["./..."].Complexity(ComplexityNesting, view.ComplexityThreshold(ComplexityNesting))
+	assert.Equal(t, ComplexityStats{Count: 5, Mean: 0.8, P90: 2, Max: 2, OverThreshold: 1}, stats)
+}
+
+func Test_SeverityViews(t *testing.T) {
+	errorIssue := &result.Issue{FromLinter: "errcheck", Pos: token.Position{Filename: "main.go", Line: 3}}
+	warningIssue := &result.Issue{FromLinter: "golint", Pos: token.Position{Filename: "main.go", Line: 5}}
+	infoIssue := &result.Issue{
+		FromLinter: "golint",
+		Text:       "exported function Foo should have comment or be unexported",
+		Pos:        token.Position{Filename: "main.go", Line: 7},
+	}
+
+	project := &Project{
+		linters: []string{"errcheck", "golint"},
+		root: &Directory{
+			Path:           ".",
+			SubDirectories: map[string]*Directory{},
+			Files: map[string]*File{
+				"main.go": {
+					Path:      "main.go",
+					LineCount: 10,
+					Issues: map[string][]*result.Issue{
+						"errcheck": {errorIssue},
+						"golint":   {warningIssue, infoIssue},
+					},
+				},
+			},
+		},
+	}
+
+	project.root.assignSeverities(&severityMapping{
+		Linters: map[string]Severity{"errcheck": SeverityError},
+		Rules:   []*severityRule{{text: regexp.MustCompile("should have comment"), Severity: SeverityInfo}},
+	}, nil)
+
+	view := project.GenerateView()
+	require.Equal(t, SeverityInfo, view.MinSeverity)
+	subView := view.SubViews["./..."]
+	require.Equal(t, map[Severity]int{
+		SeverityError:   1,
+		SeverityWarning: 1,
+		SeverityInfo:    1,
+	}, subView.SeverityCounts(view.Linters))
+
+	view = project.GenerateView(WithMinSeverity(SeverityWarning))
+	require.Equal(t, SeverityWarning, view.MinSeverity)
+	require.Equal(t, map[string][]*result.Issue{
+		"errcheck": {errorIssue},
+		"golint":   {warningIssue},
+	}, view.SubViews["./..."].Issues)
+
+	view = project.GenerateView(WithMinSeverity(SeverityWarning), WithMinSeverity(SeverityError))
+	require.Equal(t, map[string][]*result.Issue{"errcheck": {errorIssue}}, view.SubViews["./..."].Issues)
+}
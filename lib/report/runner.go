@@ -16,11 +16,17 @@ import (
 )
 
 type runner struct {
-	logger *logrus.Logger
-	cmd    *exec.Cmd
+	logger  *logrus.Logger
+	cmd     *exec.Cmd
+	timeout time.Duration
+
+	// When set the runner does not install any signal handlers and relies solely on the context
+	// passed to 'run' for interruptions.
+	ignoreSignals bool
 
 	started     bool
 	interrupted bool
+	cancelled   bool
 
 	runLock  sync.Mutex
 	killLock sync.Mutex
@@ -28,7 +34,7 @@ type runner struct {
 	memoryMonitorFunc func(*logrus.Logger, *sync.WaitGroup, chan struct{}, chan struct{})
 }
 
-func (r *runner) run() (bool, error) {
+func (r *runner) run(ctx context.Context) (bool, error) {
 	r.runLock.Lock()
 	defer r.runLock.Unlock()
 
@@ -36,29 +42,47 @@ func (r *runner) run() (bool, error) {
 		r.memoryMonitorFunc = systemMemoryMonitor
 	}
 
-	// Register clean-up handlers so that we can clean up the running linter in
-	// the case of an process-level interruption.
-	sigs := make(chan os.Signal, 1)
+	// Register clean-up handlers so that we can clean up the running linter in the case of an
+	// process-level interruption. When embedded in a host program this is left to the host which
+	// is expected to cancel the context instead.
+	var sigs chan os.Signal
+	if !r.ignoreSignals {
+		sigs = make(chan os.Signal, 1)
 
-	signal.Notify(sigs, r.getInterruptSignals()...)
+		signal.Notify(sigs, r.getInterruptSignals()...)
 
-	go r.signalHandler(sigs)
+		go r.signalHandler(sigs)
+	}
 
 	done, kill := make(chan struct{}), make(chan struct{})
 	wg := sync.WaitGroup{}
-	wg.Add(2)
+	wg.Add(3)
 
 	go r.statusMonitor(&wg, done, kill)
+	go r.contextMonitor(ctx, &wg, done)
 	go r.memoryMonitorFunc(r.logger, &wg, done, kill)
 
+	cleanUp := func() {
+		close(done)
+		if sigs != nil {
+			signal.Stop(sigs)
+			close(sigs)
+		}
+		wg.Wait()
+	}
+
 	r.killLock.Lock()
-	if r.interrupted {
-		return true, nil
+	if r.interrupted || r.cancelled {
+		r.killLock.Unlock()
+		cleanUp()
+
+		return r.interrupted, r.cancellationErr(ctx)
 	}
 
 	if err := r.cmd.Start(); err != nil {
 		r.killLock.Unlock()
 		r.logger.WithError(err).Error("Unable to run linter.")
+		cleanUp()
 
 		return false, err
 	}
@@ -69,17 +93,25 @@ func (r *runner) run() (bool, error) {
 
 	err := r.cmd.Wait()
 
-	close(done)
-	close(sigs)
-	wg.Wait()
+	cleanUp()
 
-	if err != nil && !strings.Contains(err.Error(), "killed") {
+	if r.cancelled {
+		return false, r.cancellationErr(ctx)
+	} else if err != nil && !strings.Contains(err.Error(), "killed") {
 		return false, err
 	}
 
 	return r.interrupted, nil
 }
 
+func (r *runner) cancellationErr(ctx context.Context) error {
+	if !r.cancelled {
+		return nil
+	}
+
+	return ctx.Err()
+}
+
 func (r *runner) statusMonitor(wg *sync.WaitGroup, done chan struct{}, kill chan struct{}) {
 	defer wg.Done()
 
@@ -101,6 +133,35 @@ func (r *runner) statusMonitor(wg *sync.WaitGroup, done chan struct{}, kill chan
 	}
 }
 
+func (r *runner) contextMonitor(ctx context.Context, wg *sync.WaitGroup, done chan struct{}) {
+	defer wg.Done()
+
+	runCtx := ctx
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, r.timeout)
+
+		defer cancel()
+	}
+
+	select {
+	case <-done:
+		// Don't do anything. The process has already exited.
+	case <-runCtx.Done():
+		r.killLock.Lock()
+		if ctx.Err() != nil {
+			r.logger.Info("Killing linter due to cancellation.")
+			r.cancelled = true
+		} else {
+			r.logger.Infof("Killing linter as it exceeded its timeout of %s.", r.timeout)
+			r.interrupted = true
+		}
+		r.killLinterProcess()
+		r.logger.Info("Killed.")
+		r.killLock.Unlock()
+	}
+}
+
 func (r *runner) signalHandler(sigs chan os.Signal) {
 	// A kill request corresponds to a token being received whereas a simple "done" signal is
 	// transmitted via the closing of the channel by the main goroutine.
@@ -108,11 +169,8 @@ func (r *runner) signalHandler(sigs chan os.Signal) {
 		r.killLock.Lock()
 		r.killLinterProcess()
 
-		signal.Reset()
 		os.Exit(1)
 	}
-
-	signal.Reset()
 }
 
 // The default method of monitoring memory usage uses a non-trivial strategy in order to satisfy the
@@ -11,8 +11,10 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func newRunner(logger *logrus.Logger, cliArgs []string) *runner {
-	lintCmd := exec.Command("golangci-lint", cliArgs...)
+const defaultLinterBinary = "golangci-lint"
+
+func newRunner(logger *logrus.Logger, binary string, cliArgs []string) *runner {
+	lintCmd := exec.Command(binary, cliArgs...)
 	// We need to request a dedicated process group ID to be assigned so that we can cleanly kill
 	// the entire process tree if necessary.
 	lintCmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
@@ -10,8 +10,13 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func newRunner(logger *logrus.Logger, cliArgs []string) *runner {
-	return &runner{cmd: exec.Command("golangci-lint.exe", cliArgs...)}
+const defaultLinterBinary = "golangci-lint.exe"
+
+func newRunner(logger *logrus.Logger, binary string, cliArgs []string) *runner {
+	return &runner{
+		logger: logger,
+		cmd:    exec.Command(binary, cliArgs...),
+	}
 }
 
 func (r *runner) getInterruptSignals() []os.Signal {
@@ -0,0 +1,196 @@
+package report
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/golangci/golangci-lint/pkg/result"
+	"gopkg.in/yaml.v2"
+)
+
+// Severity indicates how important it is to address an issue.
+type Severity uint8
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// DefaultSeverity is the severity of issues for which no other severity was specified.
+const DefaultSeverity = SeverityWarning
+
+// Severities lists all severities from the least to the most important one.
+var Severities = []Severity{SeverityInfo, SeverityWarning, SeverityError}
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityError:
+		return "error"
+	default:
+		return "warning"
+	}
+}
+
+// ParseSeverity returns the Severity corresponding to the given name.
+func ParseSeverity(name string) (Severity, bool) {
+	for _, severity := range Severities {
+		if severity.String() == name {
+			return severity, true
+		}
+	}
+
+	return DefaultSeverity, false
+}
+
+// UnmarshalYAML allows severities to be specified by name in configuration files.
+func (s *Severity) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err != nil {
+		return err
+	}
+
+	severity, ok := ParseSeverity(name)
+	if !ok {
+		return fmt.Errorf("unknown severity %q", name)
+	}
+
+	*s = severity
+
+	return nil
+}
+
+// severityMapping is the content of a severities file which assigns a severity to issues, e.g.:
+//
+//	default: warning
+//	linters:
+//	  errcheck: error
+//	  misspell: info
+//	rules:
+//	  - linter: golint
+//	    text: "should have comment"
+//	    severity: info
+//
+// The first rule whose linter, if specified, and text regular expression match an issue determines
+// its severity. Other issues have the severity of their linter or, failing that, the default one.
+type severityMapping struct {
+	Default *Severity           `yaml:"default"`
+	Linters map[string]Severity `yaml:"linters"`
+	Rules   []*severityRule     `yaml:"rules"`
+}
+
+type severityRule struct {
+	Linter   string   `yaml:"linter"`
+	Text     string   `yaml:"text"`
+	Severity Severity `yaml:"severity"`
+
+	text *regexp.Regexp
+}
+
+func loadSeverityMapping(path string) (*severityMapping, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := &severityMapping{}
+	if err = yaml.UnmarshalStrict(content, mapping); err != nil {
+		return nil, fmt.Errorf("could not parse severities from %q: %v", path, err)
+	}
+
+	for idx, rule := range mapping.Rules {
+		if rule.Text == "" {
+			return nil, fmt.Errorf("severity rule #%d in %q does not specify a 'text' regular expression", idx+1, path)
+		}
+
+		if rule.text, err = regexp.Compile(rule.Text); err != nil {
+			return nil, fmt.Errorf("invalid regular expression in severity rule #%d in %q: %v", idx+1, path, err)
+		}
+	}
+
+	return mapping, nil
+}
+
+// severity returns the severity of the given issue. Rules and per-linter settings take precedence
+// over the severity that golangci-lint reported for the issue, if any, which is itself preferred over
+// the default severity. The boolean is false if the issue was not assigned any severity, which is
+// only possible without a mapping.
+func (m *severityMapping) severity(issue *result.Issue, reported string) (Severity, bool) {
+	if m != nil {
+		for _, rule := range m.Rules {
+			if (rule.Linter == "" || rule.Linter == issue.FromLinter) && rule.text.MatchString(issue.Text) {
+				return rule.Severity, true
+			}
+		}
+
+		if severity, ok := m.Linters[issue.FromLinter]; ok {
+			return severity, true
+		}
+	}
+
+	// Severities reported by golangci-lint come from the project's own configuration and may use any
+	// name. Only those that correspond to one of ours are taken into account.
+	if severity, ok := ParseSeverity(strings.ToLower(reported)); ok {
+		return severity, true
+	}
+
+	if m != nil && m.Default != nil {
+		return *m.Default, true
+	}
+
+	return DefaultSeverity, m != nil
+}
+
+// assignSeverities records the severity of each of the issues in this directory's sub-tree, based on
+// the given mapping, which may be nil, and on the severities that golangci-lint reported.
+func (d *Directory) assignSeverities(mapping *severityMapping, reported map[*result.Issue]string) {
+	for _, file := range d.Files {
+		for _, issues := range file.Issues {
+			for _, issue := range issues {
+				severity, ok := mapping.severity(issue, reported[issue])
+				if !ok {
+					continue
+				}
+
+				if file.Severities == nil {
+					file.Severities = map[*result.Issue]Severity{}
+				}
+
+				file.Severities[issue] = severity
+			}
+		}
+	}
+
+	for _, subDirectory := range d.SubDirectories {
+		subDirectory.assignSeverities(mapping, reported)
+	}
+}
+
+// Severity returns the severity of the given issue of this SubView.
+func (s *SubView) Severity(issue *result.Issue) Severity {
+	return lookupSeverity(s.Severities, issue)
+}
+
+func lookupSeverity(severities map[*result.Issue]Severity, issue *result.Issue) Severity {
+	if severity, ok := severities[issue]; ok {
+		return severity
+	}
+
+	return DefaultSeverity
+}
+
+// SeverityCounts returns the number of issues of the given linters for each severity.
+func (s *SubView) SeverityCounts(linters []string) map[Severity]int {
+	counts := map[Severity]int{}
+	for _, linter := range linters {
+		for _, issue := range s.Issues[linter] {
+			counts[s.Severity(issue)]++
+		}
+	}
+
+	return counts
+}
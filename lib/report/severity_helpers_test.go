@@ -0,0 +1,173 @@
+package report
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golangci/golangci-lint/pkg/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SeverityMapping(t *testing.T) {
+	mappingFile := writeTestSeverities(t, `default: info
+linters:
+  errcheck: error
+  golint: warning
+rules:
+  - linter: golint
+    text: "should have comment"
+    severity: info
+  - text: "^G10[0-9]"
+    severity: error
+`)
+	defer os.Remove(mappingFile)
+
+	mapping, err := loadSeverityMapping(mappingFile)
+	require.NoError(t, err)
+
+	testcases := map[string]struct {
+		issue            *result.Issue
+		reported         string
+		expectedSeverity Severity
+	}{
+		"Linter": {
+			issue:            &result.Issue{FromLinter: "errcheck", Text: "Error return value is not checked"},
+			expectedSeverity: SeverityError,
+		},
+		"Rule": {
+			issue:            &result.Issue{FromLinter: "golint", Text: "exported function Foo should have comment or be unexported"},
+			expectedSeverity: SeverityInfo,
+		},
+		"RuleOtherLinter": {
+			issue:            &result.Issue{FromLinter: "misspell", Text: "exported function Foo should have comment or be unexported"},
+			expectedSeverity: SeverityInfo,
+		},
+		"RuleAnyLinter": {
+			issue:            &result.Issue{FromLinter: "gosec", Text: "G104: Errors unhandled."},
+			expectedSeverity: SeverityError,
+		},
+		"LinterWithoutMatchingRule": {
+			issue:            &result.Issue{FromLinter: "golint", Text: "don't use underscores in Go names"},
+			expectedSeverity: SeverityWarning,
+		},
+		"Default": {
+			issue:            &result.Issue{FromLinter: "lll", Text: "line is 130 characters"},
+			expectedSeverity: SeverityInfo,
+		},
+		"Reported": {
+			issue:            &result.Issue{FromLinter: "lll", Text: "line is 130 characters"},
+			reported:         "Error",
+			expectedSeverity: SeverityError,
+		},
+		"ReportedUnknown": {
+			issue:            &result.Issue{FromLinter: "lll", Text: "line is 130 characters"},
+			reported:         "blocker",
+			expectedSeverity: SeverityInfo,
+		},
+		"LinterOverridesReported": {
+			issue:            &result.Issue{FromLinter: "errcheck", Text: "Error return value is not checked"},
+			reported:         "info",
+			expectedSeverity: SeverityError,
+		},
+		"RuleOverridesReported": {
+			issue:            &result.Issue{FromLinter: "golint", Text: "exported function Foo should have comment or be unexported"},
+			reported:         "error",
+			expectedSeverity: SeverityInfo,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			severity, ok := mapping.severity(testcase.issue, testcase.reported)
+			assert.True(t, ok)
+			assert.Equal(t, testcase.expectedSeverity, severity)
+		})
+	}
+
+	linterOnlyFile := writeTestSeverities(t, "linters:\n  errcheck: error\n")
+	defer os.Remove(linterOnlyFile)
+
+	mapping, err = loadSeverityMapping(linterOnlyFile)
+	require.NoError(t, err)
+
+	severity, ok := mapping.severity(&result.Issue{FromLinter: "lll"}, "")
+	assert.True(t, ok)
+	assert.Equal(t, DefaultSeverity, severity)
+
+	// Without a mapping only the severities reported by golangci-lint are taken into account.
+	var noMapping *severityMapping
+
+	severity, ok = noMapping.severity(&result.Issue{FromLinter: "lll"}, "error")
+	assert.True(t, ok)
+	assert.Equal(t, SeverityError, severity)
+
+	_, ok = noMapping.severity(&result.Issue{FromLinter: "lll"}, "")
+	assert.False(t, ok)
+}
+
+func Test_AssignReportedSeverities(t *testing.T) {
+	var (
+		errorIssue  = &result.Issue{FromLinter: "errcheck", Text: "Error return value is not checked"}
+		plainIssue  = &result.Issue{FromLinter: "golint", Text: "don't use underscores in Go names"}
+		unusedIssue = &result.Issue{FromLinter: "unused", Text: "func `foo` is unused"}
+	)
+
+	project := &Project{
+		root: &Directory{
+			Path:           ".",
+			SubDirectories: map[string]*Directory{},
+			Files: map[string]*File{
+				"main.go": {
+					Path:      "main.go",
+					LineCount: 10,
+					Issues: map[string][]*result.Issue{
+						"errcheck": {errorIssue},
+						"golint":   {plainIssue},
+						"unused":   {unusedIssue},
+					},
+				},
+			},
+		},
+		linters: []string{"errcheck", "golint", "unused"},
+	}
+
+	// Without a severities file the severities from the golangci-lint configuration still apply.
+	project.root.assignSeverities(nil, map[*result.Issue]string{errorIssue: "error", unusedIssue: "info"})
+
+	view := project.GenerateView(WithMinSeverity(SeverityError))
+	assert.Equal(t, map[string][]*result.Issue{"errcheck": {errorIssue}}, view.SubViews["./..."].Issues)
+
+	view = project.GenerateView(WithMinSeverity(SeverityWarning))
+	assert.Equal(t, map[string][]*result.Issue{"errcheck": {errorIssue}, "golint": {plainIssue}}, view.SubViews["./..."].Issues)
+}
+
+func Test_SeverityMappingInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"UnknownField":    "linter:\n  errcheck: error\n",
+		"UnknownSeverity": "linters:\n  errcheck: fatal\n",
+		"NoText":          "rules:\n  - linter: golint\n    severity: info\n",
+		"InvalidRegexp":   "rules:\n  - text: '[foo'\n    severity: info\n",
+	} {
+		mappingFile := writeTestSeverities(t, content)
+		_, err := loadSeverityMapping(mappingFile)
+		assert.Error(t, err, "Should not accept a severity mapping with %s.", name)
+		_ = os.Remove(mappingFile)
+	}
+
+	_, err := loadSeverityMapping("non-existent.yaml")
+	assert.Error(t, err)
+}
+
+func writeTestSeverities(t *testing.T, content string) string {
+	mappingFile, err := ioutil.TempFile("", "goality-severities")
+	require.NoError(t, err)
+
+	_, err = mappingFile.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, mappingFile.Close())
+
+	return mappingFile.Name()
+}
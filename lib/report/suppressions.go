@@ -0,0 +1,64 @@
+package report
+
+import (
+	"go/scanner"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// SuppressAllLinters is the key under which 'nolint' directives that do not name specific linters
+// are recorded.
+const SuppressAllLinters = "all"
+
+// Matches 'nolint' directives such as '//nolint', '// nolint:golint,unused' or
+// '//nolint:errcheck // Reason.' and captures the list of linters, if any.
+var nolintRegexp = regexp.MustCompile(`^//\s?nolint(?::([\w-]+(?:\s*,\s*[\w-]+)*))?(?:\s|$)`)
+
+// countSuppressions returns the number of 'nolint' directives in the given Go source as well as the
+// number of directives that name each linter. Only actual comments are considered so that directives
+// mentioned in string literals are not counted. The per-linter counts are nil if the source contains
+// no directives.
+func countSuppressions(src []byte) (int, map[string]int) {
+	fileSet := token.NewFileSet()
+	file := fileSet.AddFile("", fileSet.Base(), len(src))
+
+	var s scanner.Scanner
+	s.Init(file, src, func(token.Position, string) {}, scanner.ScanComments)
+
+	var (
+		directives   int
+		suppressions map[string]int
+	)
+
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		} else if tok != token.COMMENT {
+			continue
+		}
+
+		matches := nolintRegexp.FindStringSubmatch(lit)
+		if matches == nil {
+			continue
+		}
+
+		directives++
+
+		if suppressions == nil {
+			suppressions = map[string]int{}
+		}
+
+		if matches[1] == "" {
+			suppressions[SuppressAllLinters]++
+			continue
+		}
+
+		for _, linter := range strings.Split(matches[1], ",") {
+			suppressions[strings.TrimSpace(linter)]++
+		}
+	}
+
+	return directives, suppressions
+}
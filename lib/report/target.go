@@ -0,0 +1,73 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"go/build"
+	"io"
+	"io/ioutil"
+	"runtime"
+	"strings"
+)
+
+// Target is a platform for which a project is built. Only the files that participate in the build for
+// at least one of the targets of an analysis are taken into account.
+type Target struct {
+	GOOS   string
+	GOARCH string
+}
+
+func (t Target) String() string {
+	return t.GOOS + "/" + t.GOARCH
+}
+
+// ParseTarget returns the Target corresponding to the given 'GOOS/GOARCH' specification.
+func ParseTarget(spec string) (Target, error) {
+	parts := strings.Split(spec, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Target{}, fmt.Errorf("invalid target %q: expected a 'GOOS/GOARCH' value", spec)
+	}
+
+	return Target{GOOS: parts[0], GOARCH: parts[1]}, nil
+}
+
+// defaultTarget is the platform for which 'golangci-lint' analyses a project when no targets were
+// specified. This takes any GOOS or GOARCH value passed via the linter's environment into account.
+func defaultTarget(env []string) Target {
+	target := Target{GOOS: runtime.GOOS, GOARCH: runtime.GOARCH}
+
+	for _, variable := range env {
+		switch {
+		case strings.HasPrefix(variable, "GOOS="):
+			target.GOOS = strings.TrimPrefix(variable, "GOOS=")
+		case strings.HasPrefix(variable, "GOARCH="):
+			target.GOARCH = strings.TrimPrefix(variable, "GOARCH=")
+		}
+	}
+
+	return target
+}
+
+// env returns the environment variables that make the Go toolchain build for this target.
+func (t Target) env() []string {
+	return []string{"GOOS=" + t.GOOS, "GOARCH=" + t.GOARCH}
+}
+
+// matchFile determines whether the Go file with the given name and content participates in the
+// build for this target when the specified build tags are set. This takes both '//go:build' and
+// '// +build' constraints into account as well as '_GOOS' and '_GOARCH' file name suffixes. Files
+// whose constraints can not be read are assumed to participate.
+func (t Target) matchFile(name string, content []byte, tags []string) bool {
+	ctx := build.Default
+	ctx.GOOS = t.GOOS
+	ctx.GOARCH = t.GOARCH
+	ctx.BuildTags = tags
+	ctx.CgoEnabled = build.Default.CgoEnabled && t.GOOS == runtime.GOOS && t.GOARCH == runtime.GOARCH
+	ctx.OpenFile = func(string) (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(content)), nil
+	}
+
+	match, err := ctx.MatchFile(".", name)
+
+	return err != nil || match
+}
@@ -0,0 +1,121 @@
+package report
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseTarget(t *testing.T) {
+	target, err := ParseTarget("linux/amd64")
+	require.NoError(t, err)
+	assert.Equal(t, Target{GOOS: "linux", GOARCH: "amd64"}, target)
+	assert.Equal(t, "linux/amd64", target.String())
+
+	for _, invalid := range []string{"", "linux", "linux/", "/amd64", "linux/amd64/v2"} {
+		_, err = ParseTarget(invalid)
+		assert.Error(t, err, "Should not accept %q as a target.", invalid)
+	}
+}
+
+func Test_DefaultTarget(t *testing.T) {
+	target := defaultTarget([]string{"PATH=/usr/bin", "GOOS=plan9", "GOARCH=386"})
+	assert.Equal(t, Target{GOOS: "plan9", GOARCH: "386"}, target)
+}
+
+func Test_TargetEnv(t *testing.T) {
+	opts, err := aggregateLintOpts()
+	require.NoError(t, err)
+
+	linux := Target{GOOS: "linux", GOARCH: "amd64"}
+
+	env := opts.targetEnv(linux)
+	assert.Equal(t, len(os.Environ())+2, len(env), "The environment should be inherited.")
+	assert.Equal(t, linux, defaultTarget(env))
+
+	opts, err = aggregateLintOpts(WithEnv("GOOS=plan9", "GOARCH=386"))
+	require.NoError(t, err)
+	assert.Equal(t, []Target{{GOOS: "plan9", GOARCH: "386"}}, opts.buildTargets())
+	assert.Equal(t, linux, defaultTarget(opts.targetEnv(linux)))
+}
+
+func Test_TargetMatchFile(t *testing.T) {
+	var (
+		linux   = Target{GOOS: "linux", GOARCH: "amd64"}
+		windows = Target{GOOS: "windows", GOARCH: "amd64"}
+		darwin  = Target{GOOS: "darwin", GOARCH: "arm64"}
+	)
+
+	testcases := map[string]struct {
+		name     string
+		source   string
+		tags     []string
+		expected []Target
+	}{
+		"Unconstrained": {
+			name:     "file.go",
+			source:   "package fake\n",
+			expected: []Target{linux, windows, darwin},
+		},
+		"OSSuffix": {
+			name:     "file_windows.go",
+			source:   "package fake\n",
+			expected: []Target{windows},
+		},
+		"ArchSuffix": {
+			name:     "file_arm64.go",
+			source:   "package fake\n",
+			expected: []Target{darwin},
+		},
+		"TestSuffix": {
+			name:     "file_linux_test.go",
+			source:   "package fake\n",
+			expected: []Target{linux},
+		},
+		"GoBuild": {
+			name:     "file.go",
+			source:   "//go:build darwin || windows\n\npackage fake\n",
+			expected: []Target{windows, darwin},
+		},
+		"PlusBuild": {
+			name:     "file.go",
+			source:   "// +build !windows\n\npackage fake\n",
+			expected: []Target{linux, darwin},
+		},
+		"UnixTag": {
+			name:     "file.go",
+			source:   "//go:build unix\n\npackage fake\n",
+			expected: []Target{linux, darwin},
+		},
+		"MissingTag": {
+			name:   "file.go",
+			source: "//go:build integration\n\npackage fake\n",
+		},
+		"SetTag": {
+			name:     "file.go",
+			source:   "//go:build integration && linux\n\npackage fake\n",
+			tags:     []string{"integration"},
+			expected: []Target{linux},
+		},
+		"Ignore": {
+			name:   "file.go",
+			source: "//go:build ignore\n\npackage main\n",
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			var matched []Target
+			for _, target := range []Target{linux, windows, darwin} {
+				if target.matchFile(testcase.name, []byte(testcase.source), testcase.tags) {
+					matched = append(matched, target)
+				}
+			}
+
+			assert.Equal(t, testcase.expected, matched)
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golangci/golangci-lint/pkg/report"
+	"github.com/golangci/golangci-lint/pkg/result"
+)
+
+type linterVersion struct {
+	major int
+	minor int
+	patch int
+}
+
+func (v linterVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+}
+
+var linterVersionRegexp = regexp.MustCompile(`version v?(\d+)\.(\d+)\.(\d+)`)
+
+// Parses the output of 'golangci-lint --version' which looks like:
+//
+//	golangci-lint has version 1.24.0 built from 6fd4383 on 2020-03-15T11:38:02Z
+func parseLinterVersion(output string) (linterVersion, error) {
+	matches := linterVersionRegexp.FindStringSubmatch(output)
+	if matches == nil {
+		return linterVersion{}, fmt.Errorf("could not find a version in %q", strings.TrimSpace(output))
+	}
+
+	var (
+		version = linterVersion{}
+		err     error
+	)
+
+	for idx, field := range []*int{&version.major, &version.minor, &version.patch} {
+		if *field, err = strconv.Atoi(matches[idx+1]); err != nil {
+			return linterVersion{}, err
+		}
+	}
+
+	return version, nil
+}
+
+// linterProtocol describes how to drive a range of 'golangci-lint' versions and how to interpret the
+// output that they produce.
+type linterProtocol struct {
+	version linterVersion
+
+	// Arguments that result in the issues being printed to stdout as JSON.
+	outputArgs []string
+	// Argument that disables all linters except those explicitly enabled.
+	disableAllArg string
+	// Decodes the JSON output of the linter.
+	decodeOutput func([]byte) (*lintOutput, error)
+	// Argument for excluding directories from the analysis. Left empty when the version does not
+	// support doing so via the command-line, in which case the linter is passed the retained
+	// packages explicitly instead of a recursive pattern.
+	skipDirsArg string
+}
+
+// ErrUnsupportedLinter is returned when the version of 'golangci-lint' is not one that we know how
+// to interact with.
+var ErrUnsupportedLinter = errors.New("unsupported golangci-lint version")
+
+func newLinterProtocol(version linterVersion) (*linterProtocol, error) {
+	switch {
+	case version.major == 1 && version.minor < 24:
+		return nil, fmt.Errorf("%w %s: the oldest supported version is 1.24.0", ErrUnsupportedLinter, version)
+	case version.major == 1 && version.minor < 57:
+		return &linterProtocol{
+			version:       version,
+			outputArgs:    []string{"--out-format=json"},
+			disableAllArg: "--disable-all",
+			skipDirsArg:   "--skip-dirs",
+			decodeOutput:  decodeV1Output,
+		}, nil
+	case version.major == 1:
+		return &linterProtocol{
+			version:       version,
+			outputArgs:    []string{"--out-format=json"},
+			disableAllArg: "--disable-all",
+			skipDirsArg:   "--exclude-dirs",
+			decodeOutput:  decodeV1Output,
+		}, nil
+	case version.major == 2:
+		return &linterProtocol{
+			version:       version,
+			outputArgs:    []string{"--output.json.path=stdout", "--show-stats=false"},
+			disableAllArg: "--default=none",
+			decodeOutput:  decodeV2Output,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w %s: only major versions 1 and 2 are supported", ErrUnsupportedLinter, version)
+	}
+}
+
+func (p *linterProtocol) baseArgs() []string {
+	return append([]string{
+		"run",
+		"--issues-exit-code=0",
+		"--max-issues-per-linter=0",
+		"--max-same-issues=0",
+		"--new=false",
+		"--new-from-rev=",
+	}, p.outputArgs...)
+}
+
+// lintPaths returns the paths that the linter should analyse to cover the given directory and, if
+// recursive, its sub-directories.
+func (p *linterProtocol) lintPaths(dir *Directory, recursive bool) []string {
+	if !recursive {
+		return []string{dir.Path}
+	} else if p.skipDirsArg != "" {
+		return []string{dir.Path + "/..."}
+	}
+
+	// A recursive pattern would include the excluded directories, so each of the retained
+	// directories with Go files is listed instead.
+	var (
+		paths   []string
+		collect func(*Directory)
+	)
+
+	collect = func(current *Directory) {
+		if current.hasFiles(false) {
+			paths = append(paths, current.Path)
+		}
+
+		for _, subDir := range current.SubDirectories {
+			collect(subDir)
+		}
+	}
+
+	collect(dir)
+
+	sort.Strings(paths)
+
+	return paths
+}
+
+type lintOutput struct {
+	Issues []*result.Issue
+	Report *report.Data
+	// Severities holds the non-empty severities that golangci-lint assigned to the issues. The
+	// vendored result.Issue predates this field.
+	Severities map[*result.Issue]string
+}
+
+// decode parses the JSON output of the linter. A report that carries an error is not rejected as the
+// issues that it contains may still be valid, albeit incomplete.
+func (p *linterProtocol) decode(output []byte) (*lintOutput, error) {
+	decoded, err := p.decodeOutput(output)
+	if err != nil {
+		return nil, err
+	}
+
+	if decoded.Report == nil {
+		return nil, fmt.Errorf("output of golangci-lint %s does not contain a report section", p.version)
+	}
+
+	return decoded, nil
+}
+
+// v1Issue is the layout of the issues of golangci-lint 1.x: that of the vendored result.Issue, to
+// which later versions added a severity as well as fields that we do not use.
+type v1Issue struct {
+	result.Issue
+	Severity string
+}
+
+func decodeV1Output(output []byte) (*lintOutput, error) {
+	var decoded struct {
+		Issues []*v1Issue
+		Report *report.Data
+	}
+
+	if err := json.Unmarshal(output, &decoded); err != nil {
+		return nil, err
+	}
+
+	converted := &lintOutput{Report: decoded.Report}
+	for _, issue := range decoded.Issues {
+		converted.add(&issue.Issue, issue.Severity)
+	}
+
+	return converted, nil
+}
+
+// v2Issue holds the fields of the issues of golangci-lint 2.x that we use. These versions dropped
+// the Replacement field in favour of suggested fixes whose edits are expressed as offsets within
+// the linter's own file set. As such they can not be mapped onto the source and are ignored.
+type v2Issue struct {
+	FromLinter  string
+	Text        string
+	Severity    string
+	SourceLines []string
+	LineRange   *result.Range
+	Pos         token.Position
+}
+
+func decodeV2Output(output []byte) (*lintOutput, error) {
+	var decoded struct {
+		Issues []*v2Issue
+		Report *report.Data
+	}
+
+	if err := json.Unmarshal(output, &decoded); err != nil {
+		return nil, err
+	}
+
+	converted := &lintOutput{Report: decoded.Report}
+	for _, issue := range decoded.Issues {
+		converted.add(&result.Issue{
+			FromLinter:  issue.FromLinter,
+			Text:        issue.Text,
+			SourceLines: issue.SourceLines,
+			LineRange:   issue.LineRange,
+			Pos:         issue.Pos,
+		}, issue.Severity)
+	}
+
+	return converted, nil
+}
+
+func (o *lintOutput) add(issue *result.Issue, severity string) {
+	o.Issues = append(o.Issues, issue)
+
+	if severity != "" {
+		if o.Severities == nil {
+			o.Severities = map[*result.Issue]string{}
+		}
+
+		o.Severities[issue] = severity
+	}
+}
+
+func (l *linter) detectProtocol(ctx context.Context, project *Project) (*linterProtocol, error) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	cmd := exec.CommandContext(ctx, l.opts.linterBinary(), "--version")
+	cmd.Dir = project.Path
+	cmd.Env = l.opts.linterEnv()
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		l.logger.WithError(err).Errorf("Could not determine the version of %q. Output was:\n%s%s", l.opts.linterBinary(), stdout, stderr)
+
+		return nil, fmt.Errorf("could not run %q to determine its version: %w", l.opts.linterBinary(), err)
+	}
+
+	// Older versions print their version on stdout, newer ones on stderr.
+	version, err := parseLinterVersion(stdout.String() + stderr.String())
+	if err != nil {
+		return nil, fmt.Errorf("could not determine the version of %q: %w", l.opts.linterBinary(), err)
+	}
+
+	l.logger.Debugf("Using golangci-lint version %s.", version)
+
+	return newLinterProtocol(version)
+}
+
+func (o *LintOpts) linterBinary() string {
+	if o.binaryPath != "" {
+		return o.binaryPath
+	}
+
+	return defaultLinterBinary
+}
+
+func (o *LintOpts) linterEnv() []string {
+	if len(o.env) == 0 {
+		return nil
+	}
+
+	return append(os.Environ(), o.env...)
+}
@@ -0,0 +1,232 @@
+package report
+
+import (
+	"errors"
+	"go/token"
+	"testing"
+
+	"github.com/golangci/golangci-lint/pkg/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseLinterVersion(t *testing.T) {
+	testcases := map[string]struct {
+		output      string
+		expectedErr bool
+		expected    linterVersion
+	}{
+		"Legacy": {
+			output:   "golangci-lint has version 1.24.0 built from 6fd4383 on 2020-03-15T11:38:02Z\n",
+			expected: linterVersion{major: 1, minor: 24},
+		},
+		"Modern": {
+			output:   "golangci-lint has version 2.1.6 built with go1.24.2 from eabc2638 on 2025-05-04T15:41:19Z\n",
+			expected: linterVersion{major: 2, minor: 1, patch: 6},
+		},
+		"Prefixed": {
+			output:   "golangci-lint has version v1.57.2 built with go1.22.1 from (unknown, mod sum: \"h1:xyz\") on (unknown)\n",
+			expected: linterVersion{major: 1, minor: 57, patch: 2},
+		},
+		"Garbage": {
+			output:      "command not found",
+			expectedErr: true,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			version, err := parseLinterVersion(testcase.output)
+			if testcase.expectedErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testcase.expected, version)
+			}
+		})
+	}
+}
+
+func Test_NewLinterProtocol(t *testing.T) {
+	testcases := map[string]struct {
+		version             linterVersion
+		expectedSupported   bool
+		expectedSkipDirsArg string
+	}{
+		"Legacy":        {version: linterVersion{major: 1, minor: 24}, expectedSupported: true, expectedSkipDirsArg: "--skip-dirs"},
+		"LateV1":        {version: linterVersion{major: 1, minor: 64, patch: 8}, expectedSupported: true, expectedSkipDirsArg: "--exclude-dirs"},
+		"Modern":        {version: linterVersion{major: 2, minor: 1}, expectedSupported: true},
+		"TooOld":        {version: linterVersion{major: 1, minor: 23, patch: 8}},
+		"Prehistoric":   {version: linterVersion{major: 0, minor: 9}},
+		"FromTheFuture": {version: linterVersion{major: 3}},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			protocol, err := newLinterProtocol(testcase.version)
+			if !testcase.expectedSupported {
+				assert.True(t, errors.Is(err, ErrUnsupportedLinter), "Should have rejected the version.")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testcase.expectedSkipDirsArg, protocol.skipDirsArg)
+		})
+	}
+}
+
+func Test_LintPaths(t *testing.T) {
+	legacyProtocol, err := newLinterProtocol(linterVersion{major: 1, minor: 24})
+	require.NoError(t, err)
+
+	modernProtocol, err := newLinterProtocol(linterVersion{major: 2, minor: 1})
+	require.NoError(t, err)
+
+	// The excluded directories are not part of the tree.
+	root := &Directory{
+		Path:  ".",
+		Files: map[string]*File{"file.go": {}},
+		SubDirectories: map[string]*Directory{
+			"foo": {
+				Path: "foo",
+				SubDirectories: map[string]*Directory{
+					"bar": {Path: "foo/bar", Files: map[string]*File{"file.go": {}}},
+					"baz": {Path: "foo/baz", Files: map[string]*File{"file.go": {}}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{"./..."}, legacyProtocol.lintPaths(root, true))
+	assert.Equal(t, []string{"foo/..."}, legacyProtocol.lintPaths(root.SubDirectories["foo"], true))
+	assert.Equal(t, []string{"."}, legacyProtocol.lintPaths(root, false))
+
+	assert.Equal(t, []string{".", "foo/bar", "foo/baz"}, modernProtocol.lintPaths(root, true))
+	assert.Equal(t, []string{"foo/bar", "foo/baz"}, modernProtocol.lintPaths(root.SubDirectories["foo"], true))
+	assert.Equal(t, []string{"."}, modernProtocol.lintPaths(root, false))
+}
+
+func Test_DecodeLinterOutput(t *testing.T) {
+	govetIssue := &result.Issue{
+		FromLinter:  "govet",
+		Text:        "shadow: declaration of \"err\" shadows declaration at line 11",
+		SourceLines: []string{"\t\terr := russianRoulette()"},
+		Pos:         token.Position{Filename: "file.go", Offset: 206, Line: 19, Column: 3},
+	}
+	misspellIssue := &result.Issue{
+		FromLinter:  "misspell",
+		Text:        "`recieve` is a misspelling of `receive`",
+		SourceLines: []string{"// recieve a value"},
+		Pos:         token.Position{Filename: "file.go", Offset: 40, Line: 4, Column: 4},
+	}
+	misspellFix := &result.Replacement{Inline: &result.InlineFix{StartCol: 3, Length: 7, NewString: "receive"}}
+
+	testcases := map[string]struct {
+		version            linterVersion
+		output             string
+		expectedIssues     []*result.Issue
+		expectedSeverities map[string]string
+		expectedError      string
+		expectedErr        bool
+	}{
+		"Legacy": {
+			version: linterVersion{major: 1, minor: 24},
+			output: `{"Issues":[{"FromLinter":"govet","Text":"shadow: declaration of \"err\" shadows declaration at line 11",` +
+				`"SourceLines":["\t\terr := russianRoulette()"],"Replacement":null,"Pos":{"Filename":"file.go","Offset":206,"Line":19,"Column":3}},` +
+				`{"FromLinter":"misspell","Text":"` + "`recieve` is a misspelling of `receive`" + `","SourceLines":["// recieve a value"],` +
+				`"Replacement":{"NeedOnlyDelete":false,"NewLines":null,"Inline":{"StartCol":3,"Length":7,"NewString":"receive"}},` +
+				`"Pos":{"Filename":"file.go","Offset":40,"Line":4,"Column":4}}],` +
+				`"Report":{"Linters":[{"Name":"govet","Enabled":true},{"Name":"bodyclose"}]}}`,
+			expectedIssues: []*result.Issue{govetIssue, withReplacement(misspellIssue, misspellFix)},
+		},
+		"LateV1": {
+			version: linterVersion{major: 1, minor: 64, patch: 8},
+			output: `{"Issues":[{"FromLinter":"govet","Text":"shadow: declaration of \"err\" shadows declaration at line 11","Severity":"error",` +
+				`"SourceLines":["\t\terr := russianRoulette()"],"Replacement":null,"Pos":{"Filename":"file.go","Offset":206,"Line":19,"Column":3},` +
+				`"ExpectNoLint":false,"ExpectedNoLintLinter":""},` +
+				`{"FromLinter":"misspell","Text":"` + "`recieve` is a misspelling of `receive`" + `","Severity":"","SourceLines":["// recieve a value"],` +
+				`"Replacement":{"NeedOnlyDelete":false,"NewLines":null,"Inline":{"StartCol":3,"Length":7,"NewString":"receive"}},` +
+				`"Pos":{"Filename":"file.go","Offset":40,"Line":4,"Column":4},"ExpectNoLint":false,"ExpectedNoLintLinter":""}],` +
+				`"Report":{"Linters":[{"Name":"govet","Enabled":true},{"Name":"bodyclose"}]}}`,
+			expectedIssues:     []*result.Issue{govetIssue, withReplacement(misspellIssue, misspellFix)},
+			expectedSeverities: map[string]string{"govet": "error"},
+		},
+		"Modern": {
+			version: linterVersion{major: 2, minor: 1, patch: 6},
+			output: `{"Issues":[{"FromLinter":"govet","Text":"shadow: declaration of \"err\" shadows declaration at line 11","Severity":"error",` +
+				`"SourceLines":["\t\terr := russianRoulette()"],"Pos":{"Filename":"file.go","Offset":206,"Line":19,"Column":3},` +
+				`"ExpectNoLint":false,"ExpectedNoLintLinter":""},` +
+				`{"FromLinter":"misspell","Text":"` + "`recieve` is a misspelling of `receive`" + `","Severity":"","SourceLines":["// recieve a value"],` +
+				`"SuggestedFixes":[{"Message":"","TextEdits":[{"Pos":1043,"End":1050,"NewText":"cmVjZWl2ZQ=="}]}],` +
+				`"Pos":{"Filename":"file.go","Offset":40,"Line":4,"Column":4},"ExpectNoLint":false,"ExpectedNoLintLinter":""}],` +
+				`"Report":{"Linters":[{"Name":"bodyclose"},{"Name":"govet","Enabled":true}]}}`,
+			// Suggested fixes can not be mapped onto the source.
+			expectedIssues:     []*result.Issue{govetIssue, misspellIssue},
+			expectedSeverities: map[string]string{"govet": "error"},
+		},
+		"ReportedError": {
+			version: linterVersion{major: 1, minor: 24},
+			output: `{"Issues":[{"FromLinter":"govet","Text":"shadow: declaration of \"err\" shadows declaration at line 11",` +
+				`"SourceLines":["\t\terr := russianRoulette()"],"Replacement":null,"Pos":{"Filename":"file.go","Offset":206,"Line":19,"Column":3}}],` +
+				`"Report":{"Linters":[{"Name":"govet","Enabled":true}],"Error":"context loading failed: no go files to analyze"}}`,
+			expectedIssues: []*result.Issue{govetIssue},
+			expectedError:  "context loading failed: no go files to analyze",
+		},
+		"NoReport": {
+			version:     linterVersion{major: 2},
+			output:      `{"Issues":[]}`,
+			expectedErr: true,
+		},
+		"Malformed": {
+			version:     linterVersion{major: 1, minor: 64},
+			output:      `level=error msg="Running error: context loading failed"`,
+			expectedErr: true,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			protocol, err := newLinterProtocol(testcase.version)
+			require.NoError(t, err)
+
+			output, err := protocol.decode([]byte(testcase.output))
+			if testcase.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testcase.expectedIssues, output.Issues)
+			assert.Equal(t, testcase.expectedError, output.Report.Error)
+
+			// Severities are compared per linter as the decoded issues are distinct instances.
+			var severities map[string]string
+			for issue, severity := range output.Severities {
+				if severities == nil {
+					severities = map[string]string{}
+				}
+
+				severities[issue.FromLinter] = severity
+			}
+			assert.Equal(t, testcase.expectedSeverities, severities)
+
+			var enabled []string
+			for _, linter := range output.Report.Linters {
+				if linter.Enabled {
+					enabled = append(enabled, linter.Name)
+				}
+			}
+			assert.Equal(t, []string{"govet"}, enabled)
+		})
+	}
+}
+
+func withReplacement(issue *result.Issue, replacement *result.Replacement) *result.Issue {
+	withFix := *issue
+	withFix.Replacement = replacement
+
+	return &withFix
+}
@@ -1,15 +1,20 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/Helcaraxan/goality/lib/analysis"
+	"github.com/Helcaraxan/goality/lib/fix"
 	"github.com/Helcaraxan/goality/lib/printer"
 	"github.com/Helcaraxan/goality/lib/report"
 )
@@ -41,6 +46,8 @@ func main() {
 
 	rootCmd.AddCommand(
 		initRunCommand(commonArgs),
+		initFixCommand(commonArgs),
+		initCategoriesCommand(commonArgs),
 	)
 
 	if err := rootCmd.Execute(); err != nil {
@@ -56,18 +63,56 @@ type runArgs struct {
 
 	projectPath string
 
-	config       string
-	excludePaths []string
-	linters      []string
-	depth        int
-	paths        []string
-	format       printer.FormatType
+	config          string
+	excludePaths    []string
+	excludeGlobs    []string
+	excludeRegexp   []string
+	noIgnore        bool
+	targets         []report.Target
+	buildTags       []string
+	perTarget       bool
+	maxNolint       float32
+	complexity      []report.ComplexityMetric
+	thresholds      map[string]int
+	coverProfiles   []string
+	churn           bool
+	churnSince      string
+	hotspots        bool
+	outliers        bool
+	outlierCriteria *analysis.OutlierCriteria
+	coupling        bool
+	dotFile         string
+	rules           string
+	severities      string
+	minSeverity     report.Severity
+	score           bool
+	scoreModel      string
+	effort          bool
+	effortModel     string
+	normalisation   string
+	linters         []string
+	depth           int
+	paths           []string
+	timeout         time.Duration
+	linterBinary    string
+	linterArgs      []string
+	linterEnv       []string
+	noProgress      bool
+	rateMetric      report.LineMetric
+	testCode        report.TestCodeMode
+	generated       []string
+	withGenerated   bool
+	format          printer.FormatType
 }
 
 func initRunCommand(commonArgs *commonArgs) *cobra.Command {
-	cArgs := &runArgs{commonArgs: commonArgs}
+	cArgs := &runArgs{commonArgs: commonArgs, outlierCriteria: analysis.DefaultOutlierCriteria()}
 
-	var formatValue string
+	var (
+		formatValue, rateMetricValue, testCodeValue string
+		minSeverityValue                            string
+		targetValues, complexityValues              []string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "run [path]",
@@ -86,10 +131,57 @@ Example:
 				cArgs.format = printer.FormatTypeCSV
 			case "screen":
 				cArgs.format = printer.FormatTypeScreen
+			case "json":
+				cArgs.format = printer.FormatTypeJSON
 			default:
 				return fmt.Errorf("unknown result output format %q", cArgs.format)
 			}
 
+			var ok bool
+			if cArgs.rateMetric, ok = report.ParseLineMetric(rateMetricValue); !ok {
+				return fmt.Errorf("unknown rate metric %q", rateMetricValue)
+			}
+
+			if cArgs.testCode, ok = report.ParseTestCodeMode(testCodeValue); !ok {
+				return fmt.Errorf("unknown test code mode %q", testCodeValue)
+			}
+
+			if cArgs.minSeverity, ok = report.ParseSeverity(minSeverityValue); !ok {
+				return fmt.Errorf("unknown severity %q", minSeverityValue)
+			}
+
+			if cArgs.outlierCriteria.ZScore < 0 {
+				return fmt.Errorf("invalid outlier z-score %v", cArgs.outlierCriteria.ZScore)
+			}
+
+			if cArgs.outlierCriteria.Percentile < 0 || cArgs.outlierCriteria.Percentile > 100 {
+				return fmt.Errorf("invalid outlier percentile %v", cArgs.outlierCriteria.Percentile)
+			}
+
+			for _, complexityValue := range complexityValues {
+				metric, ok := report.ParseComplexityMetric(complexityValue)
+				if !ok {
+					return fmt.Errorf("unknown complexity metric %q", complexityValue)
+				}
+
+				cArgs.complexity = append(cArgs.complexity, metric)
+			}
+
+			for name := range cArgs.thresholds {
+				if _, ok := report.ParseComplexityMetric(name); !ok {
+					return fmt.Errorf("unknown complexity metric %q", name)
+				}
+			}
+
+			for _, targetValue := range targetValues {
+				target, err := report.ParseTarget(targetValue)
+				if err != nil {
+					return err
+				}
+
+				cArgs.targets = append(cArgs.targets, target)
+			}
+
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -104,10 +196,46 @@ Example:
 
 	cmd.Flags().StringVarP(&cArgs.config, "config", "c", "", "Path to a golangci-lint configuration file that should be used.")
 	cmd.Flags().StringSliceVarP(&cArgs.excludePaths, "excludes", "e", nil, "Names of directories that should be skipped.")
+	cmd.Flags().StringSliceVar(&cArgs.excludeGlobs, "exclude-pattern", nil, "Glob patterns, relative to the project's root, of directories and files that should be skipped. Supports '**'.")
+	cmd.Flags().StringArrayVar(&cArgs.excludeRegexp, "exclude-regexp", nil, "Regular expression matching the relative paths of directories and files that should be skipped. Can be repeated.")
+	cmd.Flags().BoolVar(&cArgs.noIgnore, "no-ignore", false, "Do not skip the directories and files ignored by '.gitignore', '.goalityignore' and '.git/info/exclude' files.")
 	cmd.Flags().StringSliceVarP(&cArgs.linters, "linters", "l", nil, "Specific linters to run.")
 	cmd.Flags().IntVarP(&cArgs.depth, "depth", "d", -1, "Path granularity at which to perform the quality analysis.")
 	cmd.Flags().StringSliceVarP(&cArgs.paths, "paths", "p", nil, "Specific paths for which to provide aggregate quality analysis results.")
-	cmd.Flags().StringVarP(&formatValue, "format", "f", "screen", "Format to use when printing the results.")
+	cmd.Flags().StringVarP(&formatValue, "format", "f", "screen", "Format to use when printing the results: 'screen', 'csv' or 'json'.")
+	cmd.Flags().StringSliceVar(&cArgs.coverProfiles, "coverprofile", nil, "Coverage profiles produced by 'go test -coverprofile' to include in the results.")
+	cmd.Flags().BoolVar(&cArgs.churn, "churn", false, "Report the churn and last modification date of files based on the git history.")
+	cmd.Flags().StringVar(&cArgs.churnSince, "churn-since", "", "Only take commits more recent than this date into account for churn, e.g. '2020-01-31' or '6.months'. Implies --churn.")
+	cmd.Flags().BoolVar(&cArgs.hotspots, "hotspots", false, "Print a ranking of the paths that combine high churn with high issue rates instead of the quality report. Implies --churn.")
+	cmd.Flags().BoolVar(&cArgs.outliers, "outliers", false, "Print the packages whose issue rates are far above those of packages of comparable size instead of the quality report. Implies --coupling.")
+	cmd.Flags().Float64Var(&cArgs.outlierCriteria.ZScore, "outlier-z-score", cArgs.outlierCriteria.ZScore, "Number of standard deviations above the mean of its peers beyond which a package's issue rate is an outlier. Disabled when zero.")
+	cmd.Flags().Float64Var(&cArgs.outlierCriteria.Percentile, "outlier-percentile", cArgs.outlierCriteria.Percentile, "Percentage of its peers that must have a lower issue rate for a package's rate to be an outlier. Disabled when zero.")
+	cmd.Flags().IntVar(&cArgs.outlierCriteria.MinLines, "outlier-min-lines", cArgs.outlierCriteria.MinLines, "Number of lines below which packages are left out of the outlier detection.")
+	cmd.Flags().BoolVar(&cArgs.coupling, "coupling", false, "Report the coupling between the Go packages of the project based on 'go list'.")
+	cmd.Flags().StringVar(&cArgs.dotFile, "dot", "", "Write the import graph between the project's packages, coloured by issue rate, in Graphviz DOT format to this file. Implies --coupling.")
+	cmd.Flags().StringVar(&cArgs.rules, "rules", "", "Path to a file with architecture rules restricting the imports between the project's packages. Violations are reported by the 'goality-arch' linter.")
+	cmd.Flags().StringVar(&cArgs.severities, "severities", "", "Path to a YAML file assigning the 'error', 'warning' or 'info' severity to issues based on their linter and text.")
+	cmd.Flags().StringVar(&minSeverityValue, "min-severity", "info", "Only report issues of at least this severity: 'info', 'warning' or 'error'.")
+	cmd.Flags().BoolVar(&cArgs.score, "score", false, "Report a score from 0 to 100 and a letter grade for each path.")
+	cmd.Flags().StringVar(&cArgs.scoreModel, "score-model", "", "Path to a YAML file with the weights, caps and grades used to compute scores. Implies --score.")
+	cmd.Flags().BoolVar(&cArgs.effort, "effort", false, "Report the estimated time required to fix the issues of each path and its debt ratio.")
+	cmd.Flags().StringVar(&cArgs.effortModel, "effort-model", "", "Path to a YAML file with the per-linter and per-category fix efforts and the per-line development cost. Implies --effort.")
+	cmd.Flags().StringVar(&cArgs.normalisation, "normalisation-rules", "", "Path to a YAML file with additional rules to turn the messages of issues into the templates matched by the effort model's categories.")
+	cmd.Flags().StringSliceVar(&cArgs.generated, "generated", nil, "Glob patterns of files that should be considered as generated code in addition to those with a standard header.")
+	cmd.Flags().BoolVar(&cArgs.withGenerated, "include-generated", false, "Include generated code in the results instead of excluding it.")
+	cmd.Flags().StringVar(&testCodeValue, "test-code", "include", "How to treat test code: 'include', 'exclude' or 'split' to report it separately.")
+	cmd.Flags().StringVar(&rateMetricValue, "rate-metric", "code", "Lines to compute issue rates against: 'code', 'code-and-comments' or 'total'.")
+	cmd.Flags().StringArrayVar(&targetValues, "target", nil, "A 'GOOS/GOARCH' platform for which to analyse the project. Can be repeated. Defaults to the current platform.")
+	cmd.Flags().StringSliceVar(&cArgs.buildTags, "build-tags", nil, "Build tags to take into account when selecting files and running golangci-lint.")
+	cmd.Flags().BoolVar(&cArgs.perTarget, "per-target", false, "Print a separate report for each target platform.")
+	cmd.Flags().Float32Var(&cArgs.maxNolint, "max-nolint-density", 0, "Flag paths with more nolint directives per 1K lines than this threshold. Disabled when zero.")
+	cmd.Flags().StringSliceVar(&complexityValues, "complexity", nil, "Complexity metrics to report on: 'cyclomatic', 'cognitive', 'length' and / or 'nesting'.")
+	cmd.Flags().StringToIntVar(&cArgs.thresholds, "complexity-threshold", nil, "Per-metric thresholds above which functions are considered too complex, e.g. 'cyclomatic=15,length=80'.")
+	cmd.Flags().StringVar(&cArgs.linterBinary, "linter-binary", "", "Path to the golangci-lint binary to use instead of the one found on the PATH.")
+	cmd.Flags().StringArrayVar(&cArgs.linterArgs, "linter-arg", nil, "Additional argument to pass to golangci-lint. Can be repeated.")
+	cmd.Flags().StringArrayVar(&cArgs.linterEnv, "linter-env", nil, "Additional 'KEY=value' environment variable to set for golangci-lint. Can be repeated.")
+	cmd.Flags().BoolVar(&cArgs.noProgress, "no-progress", false, "Do not display progress information while the analysis is running.")
+	cmd.Flags().DurationVarP(&cArgs.timeout, "timeout", "t", 0, "Maximum duration of a single linter run before splitting the work up over sub-directories.")
 
 	return cmd
 }
@@ -123,10 +251,295 @@ func executeRunCommand(args *runArgs) error {
 		args.config = filepath.Join(cwd, args.config)
 	}
 
+	if args.rules != "" && !filepath.IsAbs(args.rules) {
+		args.rules = filepath.Join(cwd, args.rules)
+	}
+
+	if args.severities != "" && !filepath.IsAbs(args.severities) {
+		args.severities = filepath.Join(cwd, args.severities)
+	}
+
+	if !filepath.IsAbs(args.projectPath) {
+		args.projectPath = filepath.Join(cwd, args.projectPath)
+	}
+
+	for idx := range args.paths {
+		if filepath.IsAbs(args.paths[idx]) {
+			relPath, relErr := filepath.Rel(args.projectPath, args.paths[idx])
+			if relErr != nil {
+				return relErr
+			} else if strings.HasPrefix(relPath, "../") {
+				return fmt.Errorf("specified path %q is outside of the targeted project at %q", args.paths[idx], args.projectPath)
+			}
+
+			args.paths[idx] = relPath
+		}
+	}
+
+	lintOpts := []*report.LintOpts{
+		report.WithConfig(args.config),
+		report.WithLinters(args.linters...),
+		report.WithExcludeDirs(args.excludePaths...),
+		report.WithExcludePatterns(args.excludeGlobs...),
+		report.WithExcludeRegexps(args.excludeRegexp...),
+		report.WithTimeout(args.timeout),
+		report.WithBinary(args.linterBinary),
+		report.WithExtraArgs(args.linterArgs...),
+		report.WithEnv(args.linterEnv...),
+		report.WithGeneratedPatterns(args.generated...),
+		report.WithTargets(args.targets...),
+		report.WithBuildTags(args.buildTags...),
+		report.WithCoverProfiles(args.coverProfiles...),
+	}
+
+	if args.rules != "" {
+		lintOpts = append(lintOpts, report.WithArchitectureRules(args.rules))
+	}
+
+	if args.severities != "" {
+		lintOpts = append(lintOpts, report.WithSeverities(args.severities))
+	}
+
+	if args.churn || args.churnSince != "" || args.hotspots {
+		lintOpts = append(lintOpts, report.WithChurn(args.churnSince))
+	}
+
+	if args.coupling || args.dotFile != "" || args.outliers {
+		lintOpts = append(lintOpts, report.WithPackageMetrics())
+	}
+
+	if args.noIgnore {
+		lintOpts = append(lintOpts, report.WithoutIgnoreFiles())
+	}
+
+	// Progress is only displayed on interactive terminals and when it would not be interleaved with
+	// debug logging.
+	var progress *printer.Progress
+	if !args.noProgress && printer.IsTerminal(os.Stdout) && !args.logger.IsLevelEnabled(logrus.DebugLevel) {
+		progress = printer.NewProgress(os.Stdout)
+		lintOpts = append(lintOpts, report.WithEventHandler(progress.Handle))
+
+		progress.Start()
+	}
+
+	project, err := report.Parse(args.logger, args.projectPath, lintOpts...)
+
+	if progress != nil {
+		progress.Stop()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	viewOpts := []*report.ViewOpts{
+		report.WithDepth(args.depth),
+		report.WithPaths(args.paths...),
+		report.WithRateMetric(args.rateMetric),
+		report.WithTestCode(args.testCode),
+		report.WithSuppressionThreshold(args.maxNolint),
+		report.WithMinSeverity(args.minSeverity),
+	}
+	for name, threshold := range args.thresholds {
+		metric, _ := report.ParseComplexityMetric(name)
+		viewOpts = append(viewOpts, report.WithComplexityThreshold(metric, threshold))
+	}
+	if args.withGenerated {
+		viewOpts = append(viewOpts, report.WithGeneratedCode())
+	}
+
+	if args.dotFile != "" {
+		if err = writePackageGraph(args.dotFile, project.GenerateView(viewOpts...)); err != nil {
+			return err
+		}
+	}
+
+	printOpts := []*printer.PrintOpts{printer.WithComplexity(args.complexity...)}
+
+	if args.scoreModel != "" {
+		model, modelErr := analysis.LoadScoreModel(args.scoreModel)
+		if modelErr != nil {
+			return modelErr
+		}
+
+		printOpts = append(printOpts, printer.WithScore(model))
+	} else if args.score {
+		printOpts = append(printOpts, printer.WithScore(analysis.DefaultScoreModel()))
+	}
+
+	if args.effortModel != "" || args.effort {
+		model := analysis.DefaultEffortModel()
+		if args.effortModel != "" {
+			if model, err = analysis.LoadEffortModel(args.effortModel); err != nil {
+				return err
+			}
+		}
+
+		if args.normalisation != "" {
+			if model.Normalisation, err = analysis.LoadNormalisationRules(args.normalisation); err != nil {
+				return err
+			}
+		}
+
+		printOpts = append(printOpts, printer.WithEffort(model))
+	}
+
+	printResults := func(view *report.View) error {
+		if args.hotspots {
+			return printer.PrintHotspots(os.Stdout, analysis.HotspotRanking(view), args.format)
+		}
+
+		if args.outliers {
+			return printer.PrintOutliers(os.Stdout, analysis.OutlierRanking(view, args.outlierCriteria), args.outlierCriteria, args.format)
+		}
+
+		return printer.PrintView(os.Stdout, view, args.format, printOpts...)
+	}
+
+	if !args.perTarget {
+		return printResults(project.GenerateView(viewOpts...))
+	}
+
+	for idx, target := range project.Targets() {
+		if idx > 0 {
+			fmt.Println()
+		}
+
+		targetOpts := append([]*report.ViewOpts{report.WithTarget(target)}, viewOpts...)
+		if err = printResults(project.GenerateView(targetOpts...)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writePackageGraph(path string, view *report.View) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err = printer.PrintPackageGraph(f, view); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+type fixArgs struct {
+	*commonArgs
+
+	projectPath  string
+	config       string
+	excludePaths []string
+	excludeGlobs []string
+	buildTags    []string
+	linters      []string
+	paths        []string
+	categories   []*regexp.Regexp
+	tolerance    int
+	rules        string
+	dryRun       bool
+	timeout      time.Duration
+	linterBinary string
+	format       printer.FormatType
+}
+
+func initFixCommand(commonArgs *commonArgs) *cobra.Command {
+	fArgs := &fixArgs{commonArgs: commonArgs}
+
+	var (
+		formatValue    string
+		categoryValues []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "fix [path]",
+		Short: "Apply the fixes suggested by linters to the specified project.",
+		Long: `Run an analysis over the directory tree rooted at the specified path and apply the replacements that linters such as gofmt, goimports or misspell suggest for their issues. If no path is given this defaults to the current working directory.
+
+Files that changed since they were analysed are never modified. Once the fixes are applied the analysis is run again to report the improvement.
+
+Example:
+  goality fix --linters gofmt,misspell
+  goality fix --dry-run --paths ./cmd
+  goality fix --categories '^misspell: ' src/github.com/me/project
+`,
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			switch formatValue {
+			case "csv":
+				fArgs.format = printer.FormatTypeCSV
+			case "screen":
+				fArgs.format = printer.FormatTypeScreen
+			case "json":
+				fArgs.format = printer.FormatTypeJSON
+			default:
+				return fmt.Errorf("unknown result output format %q", formatValue)
+			}
+
+			for _, categoryValue := range categoryValues {
+				category, err := regexp.Compile(categoryValue)
+				if err != nil {
+					return fmt.Errorf("invalid category regular expression %q: %v", categoryValue, err)
+				}
+
+				fArgs.categories = append(fArgs.categories, category)
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				args = append(args, ".")
+			}
+			fArgs.projectPath = args[0]
+
+			return executeFixCommand(fArgs)
+		},
+	}
+
+	cmd.Flags().StringVarP(&fArgs.config, "config", "c", "", "Path to a golangci-lint configuration file that should be used.")
+	cmd.Flags().StringSliceVarP(&fArgs.excludePaths, "excludes", "e", nil, "Names of directories that should be skipped.")
+	cmd.Flags().StringSliceVar(&fArgs.excludeGlobs, "exclude-pattern", nil, "Glob patterns, relative to the project's root, of directories and files that should be skipped. Supports '**'.")
+	cmd.Flags().StringSliceVar(&fArgs.buildTags, "build-tags", nil, "Build tags to take into account when selecting files and running golangci-lint.")
+	cmd.Flags().StringSliceVarP(&fArgs.linters, "linters", "l", nil, "Specific linters to run and whose fixes to apply.")
+	cmd.Flags().StringSliceVarP(&fArgs.paths, "paths", "p", nil, "Specific paths to which fixes should be applied.")
+	cmd.Flags().StringArrayVar(&categoryValues, "categories", nil, "Regular expression matching the keys, i.e. '<linter>: <template>', of the issue categories whose fixes to apply. Can be repeated.")
+	cmd.Flags().IntVar(&fArgs.tolerance, "tolerance", 0, "Maximum edit distance between the messages of issues of the same category. Defaults to 10 when zero.")
+	cmd.Flags().StringVar(&fArgs.rules, "normalisation-rules", "", "Path to a YAML file with additional rules to turn the messages of issues into category templates.")
+	cmd.Flags().BoolVar(&fArgs.dryRun, "dry-run", false, "Print the fixes as a unified diff instead of applying them.")
+	cmd.Flags().StringVarP(&formatValue, "format", "f", "screen", "Format to use when printing the improvement: 'screen', 'csv' or 'json'.")
+	cmd.Flags().StringVar(&fArgs.linterBinary, "linter-binary", "", "Path to the golangci-lint binary to use instead of the one found on the PATH.")
+	cmd.Flags().DurationVarP(&fArgs.timeout, "timeout", "t", 0, "Maximum duration of a single linter run before splitting the work up over sub-directories.")
+
+	return cmd
+}
+
+func executeFixCommand(args *fixArgs) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		args.logger.WithError(err).Error("Failed to determine the current working directory")
+		return err
+	}
+
+	if args.config != "" && !filepath.IsAbs(args.config) {
+		args.config = filepath.Join(cwd, args.config)
+	}
+
 	if !filepath.IsAbs(args.projectPath) {
 		args.projectPath = filepath.Join(cwd, args.projectPath)
 	}
 
+	rules := analysis.DefaultNormalisationRules()
+	if args.rules != "" {
+		if rules, err = analysis.LoadNormalisationRules(args.rules); err != nil {
+			return err
+		}
+	}
+
 	for idx := range args.paths {
 		if filepath.IsAbs(args.paths[idx]) {
 			relPath, relErr := filepath.Rel(args.projectPath, args.paths[idx])
@@ -140,16 +553,280 @@ func executeRunCommand(args *runArgs) error {
 		}
 	}
 
-	project, err := report.Parse(
-		args.logger,
-		args.projectPath,
+	lintOpts := []*report.LintOpts{
 		report.WithConfig(args.config),
 		report.WithLinters(args.linters...),
 		report.WithExcludeDirs(args.excludePaths...),
+		report.WithExcludePatterns(args.excludeGlobs...),
+		report.WithBuildTags(args.buildTags...),
+		report.WithTimeout(args.timeout),
+		report.WithBinary(args.linterBinary),
+	}
+
+	project, err := report.Parse(args.logger, args.projectPath, lintOpts...)
+	if err != nil {
+		return err
+	}
+
+	fixOpts := []*fix.FixOpts{fix.WithLinters(args.linters...), fix.WithPaths(args.paths...)}
+
+	if len(args.categories) > 0 {
+		var categories []*analysis.IssueCategory
+		for _, category := range analysis.IssueRanking(project.GenerateView(), args.tolerance, rules) {
+			for _, pattern := range args.categories {
+				if pattern.MatchString(category.Key()) {
+					categories = append(categories, category)
+					break
+				}
+			}
+		}
+
+		if len(categories) == 0 {
+			args.logger.Info("No issue categories match the specified patterns.")
+			return nil
+		}
+
+		fixOpts = append(fixOpts, fix.WithCategories(categories...))
+	}
+
+	fixes, err := fix.Plan(project, fixOpts...)
+	if err != nil {
+		return err
+	}
+
+	if len(fixes) == 0 {
+		args.logger.Info("No fixes are available for the selected issues.")
+		return nil
+	}
+
+	if args.dryRun {
+		for _, fileFix := range fixes {
+			if _, err = fmt.Print(fileFix.Diff()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err = fix.Apply(fixes); err != nil {
+		return err
+	}
+
+	var fixed, skipped int
+	for _, fileFix := range fixes {
+		fixed += len(fileFix.Fixed)
+		skipped += len(fileFix.Skipped)
+	}
+
+	args.logger.Infof("Applied the fixes of %d issues to %d files, skipped %d fixes that were invalid or overlapping.", fixed, len(fixes), skipped)
+
+	fixedProject, err := report.Parse(args.logger, args.projectPath, lintOpts...)
+	if err != nil {
+		return err
+	}
+
+	return printer.PrintImprovement(os.Stdout, project.GenerateView(), fixedProject.GenerateView(), args.format)
+}
+
+type categoriesArgs struct {
+	*commonArgs
+
+	projectPath  string
+	config       string
+	excludePaths []string
+	excludeGlobs []string
+	buildTags    []string
+	linters      []string
+	paths        []string
+	depth        int
+	tolerance    int
+	rules        string
+	show         []*regexp.Regexp
+	matrix       bool
+	top          int
+	effort       bool
+	effortModel  string
+	timeout      time.Duration
+	linterBinary string
+	format       printer.FormatType
+}
+
+func initCategoriesCommand(commonArgs *commonArgs) *cobra.Command {
+	cArgs := &categoriesArgs{commonArgs: commonArgs}
+
+	var (
+		formatValue string
+		showValues  []string
 	)
+
+	cmd := &cobra.Command{
+		Use:   "categories [path]",
+		Short: "Group the issues of the specified project into categories of similar issues.",
+		Long: `Run an analysis over the directory tree rooted at the specified path and list the categories of similar issues from the most to the least frequent one. If no path is given this defaults to the current working directory.
+
+Each category is identified by its key, '<linter>: <template>'. With --show the location and full text of each occurrence of the matching categories is listed, grouped by directory. With --matrix the number of occurrences of the most frequent categories is reported for each directory.
+
+Example:
+  goality categories
+  goality categories --show '^golint: exported .* should have comment'
+  goality categories --matrix --depth 2 --top 5 src/github.com/me/project
+`,
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			switch formatValue {
+			case "csv":
+				cArgs.format = printer.FormatTypeCSV
+			case "screen":
+				cArgs.format = printer.FormatTypeScreen
+			case "json":
+				cArgs.format = printer.FormatTypeJSON
+			default:
+				return fmt.Errorf("unknown result output format %q", formatValue)
+			}
+
+			for _, showValue := range showValues {
+				category, err := regexp.Compile(showValue)
+				if err != nil {
+					return fmt.Errorf("invalid category regular expression %q: %v", showValue, err)
+				}
+
+				cArgs.show = append(cArgs.show, category)
+			}
+
+			if len(cArgs.show) > 0 && cArgs.matrix {
+				return errors.New("--show and --matrix can not be combined")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				args = append(args, ".")
+			}
+			cArgs.projectPath = args[0]
+
+			return executeCategoriesCommand(cArgs)
+		},
+	}
+
+	cmd.Flags().StringVarP(&cArgs.config, "config", "c", "", "Path to a golangci-lint configuration file that should be used.")
+	cmd.Flags().StringSliceVarP(&cArgs.excludePaths, "excludes", "e", nil, "Names of directories that should be skipped.")
+	cmd.Flags().StringSliceVar(&cArgs.excludeGlobs, "exclude-pattern", nil, "Glob patterns, relative to the project's root, of directories and files that should be skipped. Supports '**'.")
+	cmd.Flags().StringSliceVar(&cArgs.buildTags, "build-tags", nil, "Build tags to take into account when selecting files and running golangci-lint.")
+	cmd.Flags().StringSliceVarP(&cArgs.linters, "linters", "l", nil, "Specific linters to run.")
+	cmd.Flags().StringSliceVarP(&cArgs.paths, "paths", "p", nil, "Specific paths whose issues should be categorised.")
+	cmd.Flags().IntVarP(&cArgs.depth, "depth", "d", 1, "Path granularity of the directories in the --matrix output.")
+	cmd.Flags().IntVar(&cArgs.tolerance, "tolerance", 0, "Maximum edit distance between the messages of issues of the same category. Defaults to 10 when zero.")
+	cmd.Flags().StringVar(&cArgs.rules, "normalisation-rules", "", "Path to a YAML file with additional rules to turn the messages of issues into category templates.")
+	cmd.Flags().StringArrayVar(&showValues, "show", nil, "Regular expression matching the keys, i.e. '<linter>: <template>', of the categories whose occurrences to list. Can be repeated.")
+	cmd.Flags().BoolVar(&cArgs.matrix, "matrix", false, "Report the number of occurrences of the most frequent categories in each directory.")
+	cmd.Flags().IntVar(&cArgs.top, "top", 10, "Number of categories to include in the --matrix output. All categories are included when zero.")
+	cmd.Flags().BoolVar(&cArgs.effort, "effort", false, "Report the estimated time required to fix the issues of each category.")
+	cmd.Flags().StringVar(&cArgs.effortModel, "effort-model", "", "Path to a YAML file with the per-linter and per-category fix efforts. Implies --effort.")
+	cmd.Flags().StringVarP(&formatValue, "format", "f", "screen", "Format to use when printing the results: 'screen', 'csv' or 'json'.")
+	cmd.Flags().StringVar(&cArgs.linterBinary, "linter-binary", "", "Path to the golangci-lint binary to use instead of the one found on the PATH.")
+	cmd.Flags().DurationVarP(&cArgs.timeout, "timeout", "t", 0, "Maximum duration of a single linter run before splitting the work up over sub-directories.")
+
+	return cmd
+}
+
+func executeCategoriesCommand(args *categoriesArgs) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		args.logger.WithError(err).Error("Failed to determine the current working directory")
+		return err
+	}
+
+	if args.config != "" && !filepath.IsAbs(args.config) {
+		args.config = filepath.Join(cwd, args.config)
+	}
+
+	if !filepath.IsAbs(args.projectPath) {
+		args.projectPath = filepath.Join(cwd, args.projectPath)
+	}
+
+	rules := analysis.DefaultNormalisationRules()
+	if args.rules != "" {
+		if rules, err = analysis.LoadNormalisationRules(args.rules); err != nil {
+			return err
+		}
+	}
+
+	for idx := range args.paths {
+		if filepath.IsAbs(args.paths[idx]) {
+			relPath, relErr := filepath.Rel(args.projectPath, args.paths[idx])
+			if relErr != nil {
+				return relErr
+			} else if strings.HasPrefix(relPath, "../") {
+				return fmt.Errorf("specified path %q is outside of the targeted project at %q", args.paths[idx], args.projectPath)
+			}
+
+			args.paths[idx] = relPath
+		}
+	}
+
+	lintOpts := []*report.LintOpts{
+		report.WithConfig(args.config),
+		report.WithLinters(args.linters...),
+		report.WithExcludeDirs(args.excludePaths...),
+		report.WithExcludePatterns(args.excludeGlobs...),
+		report.WithBuildTags(args.buildTags...),
+		report.WithTimeout(args.timeout),
+		report.WithBinary(args.linterBinary),
+	}
+
+	project, err := report.Parse(args.logger, args.projectPath, lintOpts...)
 	if err != nil {
 		return err
 	}
 
-	return printer.PrintView(os.Stdout, project.GenerateView(report.WithDepth(args.depth), report.WithPaths(args.paths...)), args.format)
+	categories := analysis.IssueRanking(project.GenerateView(report.WithPaths(args.paths...)), args.tolerance, rules)
+
+	if len(args.show) > 0 {
+		var shown analysis.IssueCategories
+		for _, category := range categories {
+			for _, pattern := range args.show {
+				if pattern.MatchString(category.Key()) {
+					shown = append(shown, category)
+					break
+				}
+			}
+		}
+
+		if len(shown) == 0 {
+			args.logger.Info("No issue categories match the specified patterns.")
+			return nil
+		}
+
+		return printer.PrintCategoryOccurrences(os.Stdout, shown, args.format)
+	}
+
+	if args.matrix {
+		// The directories are restricted to the specified paths, if any.
+		viewOpts := []*report.ViewOpts{report.WithDepth(args.depth)}
+		if len(args.paths) > 0 {
+			viewOpts = []*report.ViewOpts{report.WithPaths(args.paths...)}
+		}
+
+		matrix := analysis.NewCategoryMatrix(project.GenerateView(viewOpts...), categories, args.top)
+
+		return printer.PrintCategoryMatrix(os.Stdout, matrix, args.format)
+	}
+
+	var printOpts []*printer.PrintOpts
+	if args.effortModel != "" || args.effort {
+		model := analysis.DefaultEffortModel()
+		if args.effortModel != "" {
+			if model, err = analysis.LoadEffortModel(args.effortModel); err != nil {
+				return err
+			}
+		}
+
+		// The effort model must match the templates produced by the same rules as the categories.
+		model.Normalisation = rules
+		printOpts = append(printOpts, printer.WithEffort(model))
+	}
+
+	return printer.PrintCategories(os.Stdout, categories, args.format, printOpts...)
 }